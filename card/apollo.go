@@ -1,6 +1,7 @@
 package card
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 
@@ -10,6 +11,7 @@ import (
 // Apollo is the type of the first smart ID cards.
 // Apollo cards are not manufactured anymore, and this code could be removed in the future.
 type Apollo struct {
+	readTracker
 	atr           Atr
 	smartCard     Card
 	documentFile  []byte
@@ -24,34 +26,52 @@ var APOLLO_ATR = Atr([]byte{
 })
 
 func (card *Apollo) InitCard() error {
+	return card.InitCardContext(context.Background())
+}
+
+func (card *Apollo) InitCardContext(_ context.Context) error {
 	return nil
 }
 
 func (card *Apollo) ReadCard() error {
+	return card.ReadCardContext(context.Background())
+}
+
+func (card *Apollo) ReadCardContext(ctx context.Context) error {
+	card.startRead(card.countWanted(PartDocument, PartPersonal, PartResidence, PartPortrait))
+	defer card.endRead()
 
 	var err error
 
-	card.documentFile, err = card.ReadFile(ID_DOCUMENT_FILE_LOC)
-	if err != nil {
-		return fmt.Errorf("reading document file: %w", err)
+	if card.wants(PartDocument) {
+		card.documentFile, err = card.ReadFileContext(ctx, ID_DOCUMENT_FILE_LOC)
+		if err != nil {
+			return fmt.Errorf("reading document file: %w", err)
+		}
 	}
 
-	card.personalFile, err = card.ReadFile(ID_PERSONAL_FILE_LOC)
-	if err != nil {
-		return fmt.Errorf("reading personal file: %w", err)
+	if card.wants(PartPersonal) {
+		card.personalFile, err = card.ReadFileContext(ctx, ID_PERSONAL_FILE_LOC)
+		if err != nil {
+			return fmt.Errorf("reading personal file: %w", err)
+		}
 	}
 
-	card.residenceFile, err = card.ReadFile(ID_RESIDENCE_FILE_LOC)
-	if err != nil {
-		return fmt.Errorf("reading residence file: %w", err)
+	if card.wants(PartResidence) {
+		card.residenceFile, err = card.ReadFileContext(ctx, ID_RESIDENCE_FILE_LOC)
+		if err != nil {
+			return fmt.Errorf("reading residence file: %w", err)
+		}
 	}
 
-	rsp, err := card.ReadFile(ID_PHOTO_FILE_LOC)
-	if err != nil {
-		return fmt.Errorf("reading photo file: %w", err)
-	}
+	if card.wants(PartPortrait) {
+		rsp, err := card.ReadFileContext(ctx, ID_PHOTO_FILE_LOC)
+		if err != nil {
+			return fmt.Errorf("reading photo file: %w", err)
+		}
 
-	card.photoFile = trim4b(rsp)
+		card.photoFile = trim4b(rsp)
+	}
 
 	return nil
 }
@@ -59,24 +79,32 @@ func (card *Apollo) ReadCard() error {
 func (card *Apollo) GetDocument() (document.Document, error) {
 	doc := document.IdDocument{}
 
-	err := parseIdDocumentFile(card.documentFile, &doc)
-	if err != nil {
-		return nil, fmt.Errorf("parsing document file: %w", err)
+	if card.documentFile != nil {
+		err := parseIdFile(card.documentFile, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing document file: %w", err)
+		}
 	}
 
-	err = parseIdPersonalFile(card.personalFile, &doc)
-	if err != nil {
-		return nil, fmt.Errorf("parsing personal file: %w", err)
+	if card.personalFile != nil {
+		err := parseIdFile(card.personalFile, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing personal file: %w", err)
+		}
 	}
 
-	err = parseIdResidenceFile(card.residenceFile, &doc)
-	if err != nil {
-		return nil, fmt.Errorf("parsing residence file: %w", err)
+	if card.residenceFile != nil {
+		err := parseIdFile(card.residenceFile, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing residence file: %w", err)
+		}
 	}
 
-	err = parseAndAssignIdPhotoFile(card.photoFile, &doc)
-	if err != nil {
-		return nil, fmt.Errorf("parsing photo file: %w", err)
+	if card.photoFile != nil {
+		err := parseAndAssignIdPhotoFile(card.photoFile, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing photo file: %w", err)
+		}
 	}
 
 	return &doc, nil
@@ -87,14 +115,19 @@ func (card *Apollo) Atr() Atr {
 }
 
 func (card *Apollo) ReadFile(name []byte) ([]byte, error) {
+	return card.ReadFileContext(context.Background(), name)
+}
+
+func (card *Apollo) ReadFileContext(ctx context.Context, name []byte) ([]byte, error) {
+	card.startFile()
 	output := make([]byte, 0)
 
-	_, err := card.selectFile(name, 4)
+	_, err := card.selectFile(ctx, name, 4)
 	if err != nil {
 		return nil, fmt.Errorf("selecting file: %w", err)
 	}
 
-	data, err := read(card.smartCard, 0, 6)
+	data, err := read(ctx, card.smartCard, 0, 6)
 	if err != nil {
 		return nil, fmt.Errorf("reading file header: %w", err)
 	}
@@ -105,13 +138,17 @@ func (card *Apollo) ReadFile(name []byte) ([]byte, error) {
 	length := uint(binary.LittleEndian.Uint16(data[4:]))
 	offset := uint(6)
 
+	fileLength := length
+	card.reportProgress(0, fileLength)
+
 	for length > 0 {
-		data, err := read(card.smartCard, offset, length)
+		data, err := read(ctx, card.smartCard, offset, length)
 		if err != nil {
 			return nil, fmt.Errorf("reading file: %w", err)
 		}
 
 		output = append(output, data...)
+		card.reportProgress(uint(len(output)), fileLength)
 
 		offset += uint(len(data))
 		length -= uint(len(data))
@@ -124,9 +161,9 @@ func (card *Apollo) Test() bool {
 	return true
 }
 
-func (card *Apollo) selectFile(name []byte, ne uint) ([]byte, error) {
+func (card *Apollo) selectFile(ctx context.Context, name []byte, ne uint) ([]byte, error) {
 	apu := buildAPDU(0x00, 0xA4, 0x08, 0x00, name, ne)
-	rsp, err := card.smartCard.Transmit(apu)
+	rsp, err := transmit(ctx, card.smartCard, apu)
 	if err != nil {
 		return nil, fmt.Errorf("selecting file: %w", err)
 	}
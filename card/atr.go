@@ -2,7 +2,10 @@ package card
 
 import (
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"slices"
+	"strings"
 )
 
 type Atr []byte
@@ -15,20 +18,328 @@ func (atr Atr) Is(otherAtr Atr) bool {
 	return slices.Equal(atr, otherAtr)
 }
 
-func DetectCardDocumentByAtr(atr Atr) []CardDocumentType {
-	if atr.Is(GEMALTO_ATR_1) {
-		return []CardDocumentType{GemaltoIdDocumentCardType, VehicleDocumentCardType}
-	} else if atr.Is(GEMALTO_ATR_2) || atr.Is(GEMALTO_ATR_3) {
-		return []CardDocumentType{GemaltoIdDocumentCardType, MedicalDocumentCardType, VehicleDocumentCardType}
-	} else if atr.Is(GEMALTO_ATR_4) {
-		return []CardDocumentType{GemaltoIdDocumentCardType}
-	} else if atr.Is(MEDICAL_ATR_1) || atr.Is(MEDICAL_ATR_2) {
-		return []CardDocumentType{MedicalDocumentCardType}
-	} else if atr.Is(VEHICLE_ATR_0) || atr.Is(VEHICLE_ATR_2) || atr.Is(VEHICLE_ATR_3) || atr.Is(VEHICLE_ATR_4) {
-		return []CardDocumentType{VehicleDocumentCardType}
-	} else if atr.Is(APOLLO_ATR) {
-		return []CardDocumentType{ApolloIdDocumentCardType}
+// Represents ATR decoded according to the ISO/IEC 7816-3.
+// Bytes are represented as hex strings, and absent bytes are represented with empty strings.
+type AtrInfo struct {
+	Atr             string
+	TS              string
+	Convention      string
+	T0              string
+	InterfaceBytes  []AtrInterfaceBytes
+	Protocols       []string
+	HistoricalBytes string
+	HistoricalText  string
+	TCK             string
+	TCKValid        bool
+}
+
+// Represents the i-th group of interface bytes (TAi, TBi, TCi and TDi).
+type AtrInterfaceBytes struct {
+	Index       int
+	TA          string `json:",omitempty"`
+	TB          string `json:",omitempty"`
+	TC          string `json:",omitempty"`
+	TD          string `json:",omitempty"`
+	Description []string
+}
+
+var ErrInvalidAtr = errors.New("invalid ATR")
+
+// Clock rate conversion integers indexed by the high nibble of TA1.
+var atrFi = []int{372, 372, 558, 744, 1116, 1488, 1860, 0, 0, 512, 768, 1024, 1536, 2048, 0, 0}
+
+// Maximal clock frequencies (in MHz) indexed by the high nibble of TA1.
+var atrFmax = []float64{4, 5, 6, 8, 12, 16, 20, 0, 0, 5, 7.5, 10, 15, 20, 0, 0}
+
+// Baud rate adjustment integers indexed by the low nibble of TA1.
+var atrDi = []int{0, 1, 2, 4, 8, 16, 32, 64, 12, 20, 0, 0, 0, 0, 0, 0}
+
+// Decodes TS, T0, interface bytes, historical bytes and TCK of an ATR.
+// Returns an error if the ATR is truncated or malformed.
+// Invalid TCK is not considered an error, but it is reported through `TCKValid` field.
+func ParseAtr(atr Atr) (*AtrInfo, error) {
+	if len(atr) < 2 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidAtr)
+	}
+
+	info := AtrInfo{
+		Atr:            atr.String(),
+		TS:             hexByte(atr[0]),
+		T0:             hexByte(atr[1]),
+		InterfaceBytes: []AtrInterfaceBytes{},
+		Protocols:      []string{},
+	}
+
+	switch atr[0] {
+	case 0x3B:
+		info.Convention = "direct"
+	case 0x3F:
+		info.Convention = "inverse"
+	default:
+		return nil, fmt.Errorf("%w: unknown TS byte %02X", ErrInvalidAtr, atr[0])
+	}
+
+	historicalLength := int(atr[1] & 0x0F)
+	indicator := atr[1] >> 4
+	offset := 2
+	protocolsIndicated := false
+	tckRequired := false
+	protocol := byte(0)
+
+	for i := 1; indicator != 0; i++ {
+		group := AtrInterfaceBytes{Index: i}
+		var td byte
+		tdPresent := false
+
+		for bit, target := range []*string{&group.TA, &group.TB, &group.TC, &group.TD} {
+			if indicator&(1<<bit) == 0 {
+				continue
+			}
+
+			if offset >= len(atr) {
+				return nil, fmt.Errorf("%w: interface bytes truncated", ErrInvalidAtr)
+			}
+
+			*target = hexByte(atr[offset])
+			if bit == 3 {
+				td = atr[offset]
+				tdPresent = true
+			}
+			offset++
+		}
+
+		group.Description = describeInterfaceBytes(i, group, protocol)
+
+		if tdPresent {
+			protocol = td & 0x0F
+			// T=15 is not a transmission protocol, it only qualifies the following global interface bytes
+			name := fmt.Sprintf("T=%d", protocol)
+			if protocol != 15 && !slices.Contains(info.Protocols, name) {
+				info.Protocols = append(info.Protocols, name)
+				protocolsIndicated = true
+			}
+			if protocol != 0 {
+				tckRequired = true
+			}
+			indicator = td >> 4
+		} else {
+			indicator = 0
+		}
+
+		info.InterfaceBytes = append(info.InterfaceBytes, group)
+	}
+
+	if !protocolsIndicated {
+		info.Protocols = append(info.Protocols, "T=0")
+	}
+
+	if offset+historicalLength > len(atr) {
+		return nil, fmt.Errorf("%w: historical bytes truncated", ErrInvalidAtr)
+	}
+
+	historicalBytes := atr[offset : offset+historicalLength]
+	info.HistoricalBytes = hex.EncodeToString(historicalBytes)
+	info.HistoricalText = printableText(historicalBytes)
+	offset += historicalLength
+
+	if tckRequired {
+		if offset >= len(atr) {
+			return nil, fmt.Errorf("%w: TCK missing", ErrInvalidAtr)
+		}
+
+		info.TCK = hexByte(atr[offset])
+		checksum := byte(0)
+		for _, b := range atr[1 : offset+1] {
+			checksum ^= b
+		}
+		info.TCKValid = checksum == 0
+		offset++
 	} else {
-		return []CardDocumentType{UnknownDocumentCardType}
+		info.TCKValid = true
 	}
+
+	if offset != len(atr) {
+		return nil, fmt.Errorf("%w: %d unexpected bytes at the end", ErrInvalidAtr, len(atr)-offset)
+	}
+
+	return &info, nil
+}
+
+// Returns human-readable representation of the decoded ATR. Each line describes a single part of the ATR.
+func (info *AtrInfo) String() string {
+	lines := []string{
+		"ATR: " + info.Atr,
+		"TS = " + info.TS + " (" + info.Convention + " convention)",
+		"T0 = " + info.T0,
+	}
+
+	for _, group := range info.InterfaceBytes {
+		for _, b := range []struct{ name, value string }{{"TA", group.TA}, {"TB", group.TB}, {"TC", group.TC}, {"TD", group.TD}} {
+			if b.value != "" {
+				lines = append(lines, fmt.Sprintf("%s%d = %s", b.name, group.Index, b.value))
+			}
+		}
+
+		for _, description := range group.Description {
+			lines = append(lines, "  "+description)
+		}
+	}
+
+	lines = append(lines, "Protocols: "+strings.Join(info.Protocols, ", "))
+
+	historical := "Historical bytes: " + info.HistoricalBytes
+	if info.HistoricalText != "" {
+		historical += " (\"" + info.HistoricalText + "\")"
+	}
+	lines = append(lines, historical)
+
+	if info.TCK == "" {
+		lines = append(lines, "TCK: absent")
+	} else if info.TCKValid {
+		lines = append(lines, "TCK = "+info.TCK+" (valid)")
+	} else {
+		lines = append(lines, "TCK = "+info.TCK+" (invalid)")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Describes the i-th group of interface bytes. Bytes of the groups after the second one
+// depend on the protocol indicated by the preceding TD byte.
+func describeInterfaceBytes(index int, group AtrInterfaceBytes, protocol byte) []string {
+	descriptions := []string{}
+
+	value := func(s string) byte {
+		b, _ := hex.DecodeString(s)
+		return b[0]
+	}
+
+	if group.TA != "" {
+		ta := value(group.TA)
+		switch {
+		case index == 1:
+			fi, di := atrFi[ta>>4], atrDi[ta&0x0F]
+			if fi == 0 || di == 0 {
+				descriptions = append(descriptions, "TA1: reserved values of Fi/Di")
+			} else {
+				descriptions = append(descriptions, fmt.Sprintf("TA1: Fi=%d, Di=%d, fmax=%g MHz", fi, di, atrFmax[ta>>4]))
+			}
+		case index == 2:
+			descriptions = append(descriptions, fmt.Sprintf("TA2: specific mode, protocol T=%d", ta&0x0F))
+		case protocol == 1:
+			descriptions = append(descriptions, fmt.Sprintf("TA%d: IFSC=%d", index, ta))
+		case protocol == 15:
+			descriptions = append(descriptions, fmt.Sprintf("TA%d: %s, classes %s", index, atrClockStop[ta>>6], describeClasses(ta)))
+		}
+	}
+
+	if group.TB != "" && index >= 3 {
+		tb := value(group.TB)
+		switch {
+		case protocol == 1:
+			descriptions = append(descriptions, fmt.Sprintf("TB%d: BWI=%d, CWI=%d", index, tb>>4, tb&0x0F))
+		case protocol == 15 && tb == 0:
+			descriptions = append(descriptions, fmt.Sprintf("TB%d: SPU not used", index))
+		case protocol == 15 && tb&0x80 != 0:
+			descriptions = append(descriptions, fmt.Sprintf("TB%d: SPU proprietary use", index))
+		case protocol == 15:
+			descriptions = append(descriptions, fmt.Sprintf("TB%d: SPU standard use", index))
+		}
+	}
+
+	if group.TC != "" {
+		tc := value(group.TC)
+		switch {
+		case index == 1:
+			descriptions = append(descriptions, fmt.Sprintf("TC1: extra guard time N=%d", tc))
+		case index == 2:
+			descriptions = append(descriptions, fmt.Sprintf("TC2: work waiting integer WI=%d", tc))
+		case protocol == 1 && tc&0x01 != 0:
+			descriptions = append(descriptions, fmt.Sprintf("TC%d: error detection code CRC", index))
+		case protocol == 1:
+			descriptions = append(descriptions, fmt.Sprintf("TC%d: error detection code LRC", index))
+		}
+	}
+
+	if group.TD != "" {
+		td := value(group.TD)
+		descriptions = append(descriptions, fmt.Sprintf("TD%d: protocol T=%d, next group indicator %04b", index, td&0x0F, td>>4))
+	}
+
+	return descriptions
+}
+
+// Clock stop indicators indexed by the two high bits of the first TA byte after T=15.
+var atrClockStop = []string{"clock stop not supported", "clock stop in state L", "clock stop in state H", "clock stop with no preference"}
+
+// Returns supported classes of operating conditions indicated by the low bits of the first TA byte after T=15.
+func describeClasses(ta byte) string {
+	classes := []string{}
+	for bit, class := range []string{"A", "B", "C"} {
+		if ta&(1<<bit) != 0 {
+			classes = append(classes, class)
+		}
+	}
+
+	if len(classes) == 0 {
+		return "not indicated"
+	}
+
+	return strings.Join(classes, ", ")
+}
+
+func hexByte(b byte) string {
+	return fmt.Sprintf("%02X", b)
+}
+
+// Returns text if all bytes are printable ASCII characters (CR and LF are ignored).
+func printableText(data []byte) string {
+	var text strings.Builder
+	for _, b := range data {
+		if b == '\r' || b == '\n' {
+			continue
+		}
+		if b < 0x20 || b > 0x7E {
+			return ""
+		}
+		text.WriteByte(b)
+	}
+
+	return strings.TrimSpace(text.String())
+}
+
+// Detects possible card document types from the ATR using the ATR table.
+// Types are returned in the order in which they should be probed.
+func DetectCardDocumentByAtr(atr Atr) []CardDocumentType {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return atrTable.Match(atr)
+}
+
+// Checks if the ATR is listed in the ATR table.
+// Cards with unknown ATR can still be detected by probing,
+// but their ATR should be reported so it can be added to the table.
+func IsAtrKnown(atr Atr) bool {
+	return !slices.Equal(DetectCardDocumentByAtr(atr), []CardDocumentType{UnknownDocumentCardType})
+}
+
+// Checks if the ATR of the detected card document should be reported, so it can be added to the ATR table.
+// Card types that are not listed in the ATR table (such as tachograph cards, which are issued by many countries)
+// are recognized only by probing, so their ATRs are not reported.
+// Only documents returned by the latest detection are checked. For other documents, false is returned.
+func ShouldReportAtr(cardDoc CardDocument) bool {
+	if IsAtrKnown(cardDoc.Atr()) {
+		return false
+	}
+
+	documentType, ok := detectedCardType(cardDoc)
+	if !ok {
+		return false
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	return atrTable.lists(documentType)
 }
@@ -0,0 +1,162 @@
+package card
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Default ATR table that is embedded into the program.
+//
+//go:embed atr.json
+var defaultAtrTableData []byte
+
+// Represents a single entry of the ATR table.
+// ATR pattern is given as a hex string where `.` matches any hex digit (as in the pcsc-tools smartcard_list).
+// Spaces in the pattern are ignored. Cards are listed in the order in which they should be probed.
+type AtrTableEntry struct {
+	Atr         string   `json:"atr"`
+	Description string   `json:"description"`
+	Cards       []string `json:"cards"`
+	pattern     string
+	cardTypes   []CardDocumentType
+}
+
+// Represents a list of ATR patterns with corresponding card document types.
+// The first matching entry determines the card document types.
+type AtrTable []AtrTableEntry
+
+var ErrInvalidAtrTable = errors.New("invalid ATR table")
+
+// ATR table used for detection. Guarded by registryMu.
+var atrTable = mustParseAtrTable(defaultAtrTableData)
+
+// Parses ATR table from JSON data.
+func ParseAtrTable(data []byte) (AtrTable, error) {
+	table := AtrTable{}
+
+	err := json.Unmarshal(data, &table)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidAtrTable, err)
+	}
+
+	for i := range table {
+		err := table[i].compile()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return table, nil
+}
+
+// Validates the pattern and resolves card names into card document types.
+func (entry *AtrTableEntry) compile() error {
+	err := entry.compilePattern()
+	if err != nil {
+		return err
+	}
+
+	if len(entry.Cards) == 0 {
+		return fmt.Errorf("%w: no cards for pattern %q", ErrInvalidAtrTable, entry.Atr)
+	}
+
+	entry.cardTypes = make([]CardDocumentType, 0, len(entry.Cards))
+	for _, name := range entry.Cards {
+		cardType, ok := lookupCardTypeByName(name)
+		if !ok {
+			return fmt.Errorf("%w: unknown card %q", ErrInvalidAtrTable, name)
+		}
+		entry.cardTypes = append(entry.cardTypes, cardType.Type)
+	}
+
+	return nil
+}
+
+func (entry *AtrTableEntry) compilePattern() error {
+	entry.pattern = strings.ToLower(strings.ReplaceAll(entry.Atr, " ", ""))
+	if len(entry.pattern) == 0 || len(entry.pattern)%2 != 0 {
+		return fmt.Errorf("%w: pattern %q has invalid length", ErrInvalidAtrTable, entry.Atr)
+	}
+
+	for _, c := range entry.pattern {
+		if !strings.ContainsRune("0123456789abcdef.", c) {
+			return fmt.Errorf("%w: pattern %q contains invalid character %q", ErrInvalidAtrTable, entry.Atr, c)
+		}
+	}
+
+	return nil
+}
+
+func mustParseAtrTable(data []byte) AtrTable {
+	table, err := ParseAtrTable(data)
+	if err != nil {
+		panic(err)
+	}
+
+	return table
+}
+
+// Loads ATR table from the file and puts its entries before entries of the current table.
+// In this way, user entries take precedence over the embedded ones.
+func LoadAtrTable(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading file %s: %w", path, err)
+	}
+
+	table, err := ParseAtrTable(data)
+	if err != nil {
+		return fmt.Errorf("parsing file %s: %w", path, err)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	atrTable = append(table, atrTable...)
+
+	return nil
+}
+
+// Returns card document types of the first entry that matches the ATR.
+// If no entry matches, returns a slice containing only `UnknownDocumentCardType`.
+func (table AtrTable) Match(atr Atr) []CardDocumentType {
+	atrHex := atr.String()
+
+	for _, entry := range table {
+		if entry.matches(atrHex) {
+			return append([]CardDocumentType{}, entry.cardTypes...)
+		}
+	}
+
+	return []CardDocumentType{UnknownDocumentCardType}
+}
+
+// Checks if any entry of the table lists the card document type.
+func (table AtrTable) lists(documentType CardDocumentType) bool {
+	for _, entry := range table {
+		if slices.Contains(entry.cardTypes, documentType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (entry *AtrTableEntry) matches(atrHex string) bool {
+	if len(entry.pattern) != len(atrHex) {
+		return false
+	}
+
+	for i := range len(atrHex) {
+		if entry.pattern[i] != '.' && entry.pattern[i] != atrHex[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,7 +1,10 @@
 package card_test
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"slices"
 	"testing"
 
@@ -60,3 +63,258 @@ func Test_DetectCardDocumentByAtr(t *testing.T) {
 
 	}
 }
+
+func Test_ParseAtr(t *testing.T) {
+	testCases := []struct {
+		atr                card.Atr
+		expectedProtocols  []string
+		expectedGroups     int
+		expectedHistorical string
+		expectedText       string
+		expectedTCK        string
+		expectedTCKValid   bool
+	}{
+		{
+			atr:                card.APOLLO_ATR,
+			expectedProtocols:  []string{"T=1"},
+			expectedGroups:     3,
+			expectedHistorical: "8073ff614083000000",
+			expectedText:       "",
+			expectedTCK:        "DF",
+			expectedTCKValid:   true,
+		},
+		{
+			atr:                card.MEDICAL_ATR_1,
+			expectedProtocols:  []string{"T=1"},
+			expectedGroups:     3,
+			expectedHistorical: "52465a4f",
+			expectedText:       "RFZO",
+			expectedTCK:        "ED",
+			expectedTCKValid:   true,
+		},
+		{
+			atr:                card.GEMALTO_ATR_1,
+			expectedProtocols:  []string{"T=1"},
+			expectedGroups:     3,
+			expectedHistorical: "80318065b0850201f3120fff829000",
+			expectedText:       "",
+			expectedTCK:        "79",
+			expectedTCKValid:   true,
+		},
+		{
+			atr:                card.Atr{0x3B, 0x81, 0x80, 0x01, 0x80, 0x00},
+			expectedProtocols:  []string{"T=0", "T=1"},
+			expectedGroups:     2,
+			expectedHistorical: "80",
+			expectedText:       "",
+			expectedTCK:        "00",
+			expectedTCKValid:   false,
+		},
+		{
+			atr:                card.Atr{0x3B, 0x80, 0x81, 0xB1, 0xFE, 0x45, 0x1F, 0x03, 0x17},
+			expectedProtocols:  []string{"T=1"},
+			expectedGroups:     4,
+			expectedHistorical: "",
+			expectedText:       "",
+			expectedTCK:        "17",
+			expectedTCKValid:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("Case %s", testCase.atr), func(t *testing.T) {
+			info, err := card.ParseAtr(testCase.atr)
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if !slices.Equal(info.Protocols, testCase.expectedProtocols) {
+				t.Errorf("Expected protocols %v, but got %v", testCase.expectedProtocols, info.Protocols)
+			}
+
+			if len(info.InterfaceBytes) != testCase.expectedGroups {
+				t.Errorf("Expected %d groups of interface bytes, but got %d", testCase.expectedGroups, len(info.InterfaceBytes))
+			}
+
+			if info.HistoricalBytes != testCase.expectedHistorical {
+				t.Errorf("Expected historical bytes %s, but got %s", testCase.expectedHistorical, info.HistoricalBytes)
+			}
+
+			if info.HistoricalText != testCase.expectedText {
+				t.Errorf("Expected historical text %q, but got %q", testCase.expectedText, info.HistoricalText)
+			}
+
+			if info.TCK != testCase.expectedTCK {
+				t.Errorf("Expected TCK %s, but got %s", testCase.expectedTCK, info.TCK)
+			}
+
+			if info.TCKValid != testCase.expectedTCKValid {
+				t.Errorf("Expected TCK validity %v, but got %v", testCase.expectedTCKValid, info.TCKValid)
+			}
+		})
+	}
+}
+
+func Test_ParseAtrDescription(t *testing.T) {
+	info, err := card.ParseAtr(card.Atr{0x3B, 0x80, 0x81, 0xB1, 0xFE, 0x45, 0x1F, 0x03, 0x17})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	expected := [][]string{
+		{"TD1: protocol T=1, next group indicator 1000"},
+		{"TD2: protocol T=1, next group indicator 1011"},
+		{"TA3: IFSC=254", "TB3: BWI=4, CWI=5", "TD3: protocol T=15, next group indicator 0001"},
+		{"TA4: clock stop not supported, classes A, B"},
+	}
+
+	for i, group := range info.InterfaceBytes {
+		if !slices.Equal(group.Description, expected[i]) {
+			t.Errorf("Expected description %v of group %d, but got %v", expected[i], group.Index, group.Description)
+		}
+	}
+}
+
+func Test_ParseAtrInvalid(t *testing.T) {
+	testCases := []card.Atr{
+		{},
+		{0x3B},
+		{0x12, 0x00},
+		{0x3B, 0x10},
+		{0x3B, 0x02, 0x01},
+		{0x3B, 0x80, 0x01},
+		{0x3B, 0x00, 0x00},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("Case %s", testCase), func(t *testing.T) {
+			_, err := card.ParseAtr(testCase)
+			if !errors.Is(err, card.ErrInvalidAtr) {
+				t.Errorf("Expected error %v, but got %v", card.ErrInvalidAtr, err)
+			}
+		})
+	}
+}
+
+func Test_AtrTableMatch(t *testing.T) {
+	table, err := card.ParseAtrTable([]byte(`[
+		{"atr": "3B 9E 96 80 31 FE 45 53 43 45 20 38 2E 30 2D 43 .. 56 30 0D 0A ..", "cards": ["gemalto"]},
+		{"atr": "3b9d1.8131......31c0694d54434f537302......", "cards": ["vehicle", "medical"]}
+	]`))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	testCases := []struct {
+		atr            card.Atr
+		expectedResult []card.CardDocumentType
+	}{
+		{
+			atr:            card.GEMALTO_ATR_3,
+			expectedResult: []card.CardDocumentType{card.GemaltoIdDocumentCardType},
+		},
+		{
+			atr:            card.GEMALTO_ATR_4,
+			expectedResult: []card.CardDocumentType{card.GemaltoIdDocumentCardType},
+		},
+		{
+			atr:            card.VEHICLE_ATR_2,
+			expectedResult: []card.CardDocumentType{card.VehicleDocumentCardType, card.MedicalDocumentCardType},
+		},
+		{
+			atr:            card.VEHICLE_ATR_4,
+			expectedResult: []card.CardDocumentType{card.VehicleDocumentCardType, card.MedicalDocumentCardType},
+		},
+		{
+			atr:            card.MEDICAL_ATR_1,
+			expectedResult: []card.CardDocumentType{card.UnknownDocumentCardType},
+		},
+		{
+			atr:            card.Atr{},
+			expectedResult: []card.CardDocumentType{card.UnknownDocumentCardType},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("Case %s", testCase.atr), func(t *testing.T) {
+			result := table.Match(testCase.atr)
+			if !slices.Equal(testCase.expectedResult, result) {
+				t.Errorf("Expected response to be %v, but it is %v", testCase.expectedResult, result)
+			}
+		})
+	}
+}
+
+func Test_ParseAtrTableInvalid(t *testing.T) {
+	testCases := []string{
+		`{}`,
+		`[{"atr": "3b0", "cards": ["gemalto"]}]`,
+		`[{"atr": "3bxx", "cards": ["gemalto"]}]`,
+		`[{"atr": "", "cards": ["gemalto"]}]`,
+		`[{"atr": "3b00", "cards": []}]`,
+		`[{"atr": "3b00", "cards": ["passport"]}]`,
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase, func(t *testing.T) {
+			_, err := card.ParseAtrTable([]byte(testCase))
+			if !errors.Is(err, card.ErrInvalidAtrTable) {
+				t.Errorf("Expected error %v, but got %v", card.ErrInvalidAtrTable, err)
+			}
+		})
+	}
+}
+
+func Test_LoadAtrTable(t *testing.T) {
+	atr := card.Atr{0x3B, 0x02, 0x12, 0x34}
+	path := filepath.Join(t.TempDir(), "atr.json")
+
+	err := os.WriteFile(path, []byte(`[{"atr": "3b02....", "cards": ["tachograph"]}]`), 0600)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	t.Run("Loaded", func(t *testing.T) {
+		card.RestoreAtrTable(t)
+
+		err := card.LoadAtrTable(path)
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		result := card.DetectCardDocumentByAtr(atr)
+		if !slices.Equal(result, []card.CardDocumentType{card.TachographDocumentCardType}) {
+			t.Errorf("Expected user entry to be matched, but got %v", result)
+		}
+
+		result = card.DetectCardDocumentByAtr(card.APOLLO_ATR)
+		if !slices.Equal(result, []card.CardDocumentType{card.ApolloIdDocumentCardType}) {
+			t.Errorf("Expected embedded entry to be matched, but got %v", result)
+		}
+	})
+
+	if card.IsAtrKnown(atr) {
+		t.Errorf("Expected user entry to be removed after the test")
+	}
+}
+
+func Test_IsAtrKnown(t *testing.T) {
+	testCases := []struct {
+		atr            card.Atr
+		expectedResult bool
+	}{
+		{atr: card.APOLLO_ATR, expectedResult: true},
+		{atr: card.GEMALTO_ATR_4, expectedResult: true},
+		{atr: card.VEHICLE_ATR_4, expectedResult: true},
+		{atr: card.Atr{}, expectedResult: false},
+		{atr: card.Atr{0x3B, 0x00}, expectedResult: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("Case %s", testCase.atr), func(t *testing.T) {
+			if card.IsAtrKnown(testCase.atr) != testCase.expectedResult {
+				t.Errorf("Expected %v for ATR %s", testCase.expectedResult, testCase.atr)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/ubavic/bas-celik/card/cardErrors"
@@ -19,11 +20,49 @@ type BER struct {
 	children  []BER  // Branch nodes children. Should only exist if primitive is false.
 }
 
+// Maximal depth of nested constructed nodes accepted by ParseBER.
+const maxDepth = 32
+
+// Returned when BER data can't be parsed.
+type ParseError struct {
+	Offset uint32   // Offset of the field that can't be parsed, from the start of the parsed data
+	Path   []uint32 // Tags of constructed nodes that contain the field
+	Err    error
+}
+
+func (err *ParseError) Error() string {
+	return fmt.Sprintf("parsing BER at offset %d (path %X): %v", err.Offset, err.Path, err.Err)
+}
+
+func (err *ParseError) Unwrap() error {
+	return err.Err
+}
+
 // Parses BER data (described in ISO/IEC 7816-4 (2005)).
+// Order of the nodes is preserved, so the parsed tree can be encoded back into the same data.
+// Nodes with the same tag on the same level are merged.
+// Returns ParseError if the data is malformed or empty.
 func ParseBER(data []byte) (*BER, error) {
-	primitive, constructed, err := parseBERLayer(data)
+	if len(data) == 0 {
+		return nil, &ParseError{Offset: 0, Path: []uint32{}, Err: fmt.Errorf("%w: empty data", cardErrors.ErrInvalidFormat)}
+	}
+
+	return parseBER(data, 0, []uint32{})
+}
+
+// Parses BER data that starts at the given offset of the complete data and that is nested inside nodes with the given path.
+func parseBER(data []byte, base uint32, path []uint32) (*BER, error) {
+	if len(path) > maxDepth {
+		return nil, &ParseError{Offset: base, Path: path, Err: fmt.Errorf("%w: too deeply nested", cardErrors.ErrInvalidFormat)}
+	}
 
+	fields, err := parseBERLayer(data)
 	if err != nil {
+		var parseError *ParseError
+		if errors.As(err, &parseError) {
+			parseError.Offset += base
+			parseError.Path = path
+		}
 		return nil, err
 	}
 
@@ -34,40 +73,32 @@ func ParseBER(data []byte) (*BER, error) {
 		children:  []BER{},
 	}
 
-	for t, v := range primitive {
+	for _, field := range fields {
 		val := BER{
-			tag:       t,
+			tag:       field.tag,
 			primitive: true,
-			data:      v,
+			data:      field.value,
 			children:  nil,
 		}
 
-		err = ber.add(val)
-		if err != nil {
-			return nil, fmt.Errorf("adding primitive value: %w", err)
-		}
-	}
+		if !field.primitive {
+			subBer, err := parseBER(field.value, base+field.valueOffset, append(slices.Clip(path), field.tag))
+			if err != nil {
+				return nil, err
+			}
 
-	for t, v := range constructed {
-		subBer, err := ParseBER(v)
-		if err != nil {
-			return nil, err
-		}
-		val := BER{
-			tag:       t,
-			primitive: false,
-			data:      nil,
-			children:  subBer.children,
+			val.primitive = false
+			val.data = nil
+			val.children = subBer.children
 		}
 
 		err = ber.add(val)
 		if err != nil {
-			return nil, fmt.Errorf("adding primitive value: %w", err)
+			return nil, &ParseError{Offset: base + field.offset, Path: path, Err: fmt.Errorf("%w: adding tag %X: %w", cardErrors.ErrInvalidFormat, field.tag, err)}
 		}
 	}
 
 	return &ber, nil
-
 }
 
 // Access node's data with the provided address composed as a list of tags.
@@ -148,45 +179,54 @@ func (into *BER) Merge(new BER) error {
 	return nil
 }
 
+// Represents a single encoded field of a BER layer.
+type berField struct {
+	tag         uint32
+	primitive   bool
+	value       []byte
+	offset      uint32 // Offset of the tag
+	valueOffset uint32 // Offset of the value
+}
+
 // Parses one level of BER-TLV encoded data.
-// Returns map of primitive and constructed fields.
-func parseBERLayer(data []byte) (map[uint32][]byte, map[uint32][]byte, error) {
-	primF := make(map[uint32][]byte)
-	consF := make(map[uint32][]byte)
+// Returns fields in the order in which they appear in data.
+// Returned errors are of type ParseError, with the offset relative to the start of data.
+func parseBERLayer(data []byte) ([]berField, error) {
+	fields := []berField{}
 	offset := uint32(0)
 
-	for {
+	for offset < uint32(len(data)) {
+		fieldOffset := offset
+
 		tag, primitive, offsetDelta, err := ParseTag(data[offset:])
 		if err != nil {
-			return nil, nil, err
+			return nil, &ParseError{Offset: fieldOffset, Err: fmt.Errorf("parsing tag: %w", err)}
 		}
 
 		offset += offsetDelta
 
 		length, offsetDelta, err := ParseLength(data[offset:])
 		if err != nil {
-			return nil, nil, err
+			return nil, &ParseError{Offset: fieldOffset, Err: fmt.Errorf("parsing length of tag %X: %w", tag, err)}
 		}
 
 		offset += offsetDelta
-		value := data[offset : offset+length]
-
-		if primitive {
-			primF[tag] = value
-		} else {
-			consF[tag] = value
+		if uint64(offset)+uint64(length) > uint64(len(data)) {
+			return nil, &ParseError{Offset: fieldOffset, Err: fmt.Errorf("%w: value of tag %X exceeds data", cardErrors.ErrInvalidLength, tag)}
 		}
 
-		offset += length
+		fields = append(fields, berField{
+			tag:         tag,
+			primitive:   primitive,
+			value:       data[offset : offset+length],
+			offset:      fieldOffset,
+			valueOffset: offset,
+		})
 
-		if offset == uint32(len(data)) {
-			break
-		} else if offset > uint32(len(data)) {
-			return nil, nil, cardErrors.ErrInvalidLength
-		}
+		offset += length
 	}
 
-	return primF, consF, nil
+	return fields, nil
 }
 
 func (tree *BER) AssignFrom(target *string, address ...uint32) {
@@ -240,7 +280,7 @@ func ParseLength(data []byte) (uint32, uint32, error) {
 		length = uint32(binary.BigEndian.Uint16(data[1:]))
 		offset = 3
 	} else if firstByte == 0x83 && len(data) >= 4 {
-		length = 0x00FFFFFF & binary.BigEndian.Uint32(data)
+		length = uint32(data[1])<<16 | uint32(data[2])<<8 | uint32(data[3])
 		offset = 4
 	} else if firstByte == 0x84 && len(data) >= 5 {
 		length = binary.BigEndian.Uint32(data[1:])
@@ -1,7 +1,12 @@
 package ber
 
 import (
+	"bytes"
 	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/ubavic/bas-celik/card/cardErrors"
@@ -156,3 +161,273 @@ func Test_parseBerTag(t *testing.T) {
 		}
 	}
 }
+
+func Test_EncodeLength(t *testing.T) {
+	testCases := []struct {
+		length       uint32
+		expectedData []byte
+	}{
+		{length: 0, expectedData: []byte{0x00}},
+		{length: 0x7F, expectedData: []byte{0x7F}},
+		{length: 0x80, expectedData: []byte{0x81, 0x80}},
+		{length: 0xFF, expectedData: []byte{0x81, 0xFF}},
+		{length: 0x100, expectedData: []byte{0x82, 0x01, 0x00}},
+		{length: 0x123456, expectedData: []byte{0x83, 0x12, 0x34, 0x56}},
+		{length: 0x12345678, expectedData: []byte{0x84, 0x12, 0x34, 0x56, 0x78}},
+	}
+
+	for _, testCase := range testCases {
+		data := EncodeLength(testCase.length)
+		if !bytes.Equal(data, testCase.expectedData) {
+			t.Errorf("Expected length %d to be encoded as %X, but it is %X", testCase.length, testCase.expectedData, data)
+		}
+
+		length, parsedBytes, err := ParseLength(data)
+		if err != nil || length != testCase.length || parsedBytes != uint32(len(data)) {
+			t.Errorf("Expected encoded length %X to be parsed as %d, but it is %d (%v)", data, testCase.length, length, err)
+		}
+	}
+}
+
+func Test_EncodeTag(t *testing.T) {
+	testCases := []struct {
+		tag           uint32
+		expectedData  []byte
+		expectedError error
+	}{
+		{tag: 0x71, expectedData: []byte{0x71}},
+		{tag: 0x9F33, expectedData: []byte{0x9F, 0x33}},
+		{tag: 0xBFAF55, expectedData: []byte{0xBF, 0xAF, 0x55}},
+		{tag: 0x1F, expectedError: cardErrors.ErrInvalidFormat},
+		{tag: 0x7133, expectedError: cardErrors.ErrInvalidFormat},
+		{tag: 0x9FAF, expectedError: cardErrors.ErrInvalidFormat},
+		{tag: 0x01020304, expectedError: cardErrors.ErrInvalidFormat},
+	}
+
+	for _, testCase := range testCases {
+		data, err := EncodeTag(testCase.tag)
+		if !errors.Is(err, testCase.expectedError) {
+			t.Errorf("Expected error '%v', but error is '%v'", testCase.expectedError, err)
+		}
+
+		if !bytes.Equal(data, testCase.expectedData) {
+			t.Errorf("Expected tag %X to be encoded as %X, but it is %X", testCase.tag, testCase.expectedData, data)
+		}
+	}
+}
+
+func Test_EncodeRoundTrip(t *testing.T) {
+	longValue := bytes.Repeat([]byte{'A'}, 300)
+
+	tree := NewConstructed(0,
+		NewConstructed(0x71,
+			NewPrimitive(0x80, []byte("01")),
+			NewConstructed(0xA1, NewPrimitive(0x8F, []byte("BG123AB"))),
+			NewPrimitive(0x9F33, longValue),
+		),
+		NewConstructed(0x72, NewPrimitive(0xC1, []byte{})),
+	)
+
+	data, err := tree.Encode()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	expectedStart := []byte{0x71, 0x82, 0x01, 0x40, 0x80, 0x02, '0', '1', 0xA1, 0x09, 0x8F, 0x07}
+	if !bytes.HasPrefix(data, expectedStart) {
+		t.Errorf("Expected data to start with %X, but it is %X", expectedStart, data[:len(expectedStart)])
+	}
+
+	parsed, err := ParseBER(data)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	value, err := parsed.access(0x71, 0x9F33)
+	if err != nil || !bytes.Equal(value, longValue) {
+		t.Errorf("Expected long value to be parsed, but got %d bytes (%v)", len(value), err)
+	}
+
+	encoded, err := parsed.Encode()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if !bytes.Equal(encoded, data) {
+		t.Errorf("Expected parsed tree to be encoded as %X, but it is %X", data, encoded)
+	}
+}
+
+func Test_EncodeInvalidNode(t *testing.T) {
+	_, err := NewConstructed(0, NewPrimitive(0x71, []byte{0x01})).Encode()
+	if !errors.Is(err, cardErrors.ErrInvalidFormat) {
+		t.Errorf("Expected error for constructed tag in primitive node, but got %v", err)
+	}
+
+	_, err = NewConstructed(0x80).Encode()
+	if !errors.Is(err, cardErrors.ErrInvalidFormat) {
+		t.Errorf("Expected error for primitive tag in constructed node, but got %v", err)
+	}
+}
+
+func Test_Navigation(t *testing.T) {
+	tree := NewConstructed(0,
+		NewConstructed(0x71,
+			NewPrimitive(0x80, []byte("01")),
+			NewConstructed(0xA1, NewPrimitive(0x80, []byte{0x00, 0xFF})),
+		),
+		NewConstructed(0x72, NewPrimitive(0xC1, []byte("BG"))),
+	)
+
+	node, ok := tree.Get(0x71, 0xA1)
+	if !ok || node.Primitive() || len(node.Children()) != 1 {
+		t.Fatalf("Expected constructed node, but got %v", node)
+	}
+
+	if _, ok := tree.Get(0x71, 0xA2); ok {
+		t.Errorf("Expected missing node not to be found")
+	}
+
+	value, err := tree.Value(0x72, 0xC1)
+	if err != nil || string(value) != "BG" {
+		t.Errorf("Expected value BG, but got %q (%v)", value, err)
+	}
+
+	found := tree.Find(0x80)
+	if len(found) != 2 || string(found[0].Data()) != "01" || found[1].Data()[1] != 0xFF {
+		t.Errorf("Expected both nodes with the tag in order, but got %v", found)
+	}
+
+	addresses := []string{}
+	tree.Walk(func(address []uint32, node BER) bool {
+		addresses = append(addresses, fmt.Sprintf("%X", address))
+		return node.Tag() != 0x72
+	})
+
+	expectedAddresses := []string{"[]", "[71]", "[71 80]", "[71 A1]", "[71 A1 80]", "[72]"}
+	if !slices.Equal(addresses, expectedAddresses) {
+		t.Errorf("Expected addresses %v, but got %v", expectedAddresses, addresses)
+	}
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	expectedJson := `{"children":[{"tag":"71","children":[{"tag":"80","value":"3031","text":"01"},{"tag":"A1","children":[{"tag":"80","value":"00ff"}]}]},{"tag":"72","children":[{"tag":"C1","value":"4247","text":"BG"}]}]}`
+	if string(data) != expectedJson {
+		t.Errorf("Expected JSON %s, but got %s", expectedJson, data)
+	}
+}
+
+func Test_ParseBERError(t *testing.T) {
+	testCases := []struct {
+		data           []byte
+		expectedOffset uint32
+		expectedPath   []uint32
+		expectedError  error
+	}{
+		{
+			data:           []byte{0x71, 0x05, 0x80, 0x01, 0x30},
+			expectedOffset: 0,
+			expectedPath:   []uint32{},
+			expectedError:  cardErrors.ErrInvalidLength,
+		},
+		{
+			data:           []byte{0x80, 0x00, 0x71, 0x05, 0x80, 0x01, 0x30, 0xA1, 0x05},
+			expectedOffset: 7,
+			expectedPath:   []uint32{0x71},
+			expectedError:  cardErrors.ErrInvalidLength,
+		},
+		{
+			data:           []byte{0x71, 0x06, 0xA1, 0x04, 0x8F, 0x03, 0x41, 0x42},
+			expectedOffset: 4,
+			expectedPath:   []uint32{0x71, 0xA1},
+			expectedError:  cardErrors.ErrInvalidLength,
+		},
+		{
+			data:           []byte{0x71},
+			expectedOffset: 0,
+			expectedPath:   []uint32{},
+			expectedError:  cardErrors.ErrInvalidLength,
+		},
+		{
+			data:           []byte{},
+			expectedOffset: 0,
+			expectedPath:   []uint32{},
+			expectedError:  cardErrors.ErrInvalidFormat,
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			_, err := ParseBER(testCase.data)
+
+			var parseError *ParseError
+			if !errors.As(err, &parseError) || !errors.Is(err, testCase.expectedError) {
+				t.Fatalf("Expected parse error '%v', but got '%v'", testCase.expectedError, err)
+			}
+
+			if parseError.Offset != testCase.expectedOffset || !slices.Equal(parseError.Path, testCase.expectedPath) {
+				t.Errorf("Expected error at offset %d with path %X, but got offset %d with path %X", testCase.expectedOffset, testCase.expectedPath, parseError.Offset, parseError.Path)
+			}
+		})
+	}
+}
+
+func Test_ParseBERDepth(t *testing.T) {
+	data := []byte{}
+	for range maxDepth + 2 {
+		data = append([]byte{0x71, byte(len(data))}, data...)
+	}
+
+	_, err := ParseBER(data)
+	if !errors.Is(err, cardErrors.ErrInvalidFormat) {
+		t.Errorf("Expected error for deeply nested data, but got %v", err)
+	}
+}
+
+func Test_Unmarshal(t *testing.T) {
+	tree := NewConstructed(0,
+		NewConstructed(0x71,
+			NewPrimitive(0x82, []byte("20200115")),
+			NewConstructed(0xA3, NewPrimitive(0x87, []byte("FIAT"))),
+			NewPrimitive(0x9F33, []byte("RS")),
+		),
+		NewConstructed(0x72,
+			NewConstructed(0xA1, NewConstructed(0xA9, NewPrimitive(0x83, []byte("USER")))),
+			NewConstructed(0xA5, NewPrimitive(0x9F, []byte("5000"))),
+		),
+	)
+
+	var doc struct {
+		Date  string            `ber:"71.82" date:"ymd"`
+		Make  string            `ber:"71.A3.87"`
+		State string            `ber:"71.9F33"`
+		User  string            `ber:"71.A1.A9.83|72.A1.A9.83"`
+		Type  string            `ber:"71.A3.88"`
+		Extra map[string][]byte `ber:"*"`
+	}
+
+	err := Unmarshal(tree, &doc)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if doc.Date != "15.01.2020" || doc.Make != "FIAT" || doc.State != "RS" || doc.User != "USER" || doc.Type != "" {
+		t.Errorf("Unexpected result %+v", doc)
+	}
+
+	if len(doc.Extra) != 1 || string(doc.Extra["72.A5.9F"]) != "5000" {
+		t.Errorf("Expected single unmapped node, but got %v", doc.Extra)
+	}
+
+	var invalid struct {
+		Make string `ber:"71.G3"`
+	}
+
+	err = Unmarshal(tree, &invalid)
+	if err == nil {
+		t.Errorf("Expected error for invalid address")
+	}
+}
@@ -0,0 +1,123 @@
+package ber
+
+import (
+	"fmt"
+
+	"github.com/ubavic/bas-celik/card/cardErrors"
+)
+
+// Creates a primitive (leaf) node with the given tag and data. Data is not copied.
+func NewPrimitive(tag uint32, data []byte) BER {
+	return BER{
+		tag:       tag,
+		primitive: true,
+		data:      data,
+		children:  nil,
+	}
+}
+
+// Creates a constructed node with the given tag and children.
+// Node with the tag 0 represents the root of the tree and it is encoded only as a concatenation of its children.
+func NewConstructed(tag uint32, children ...BER) BER {
+	return BER{
+		tag:       tag,
+		primitive: false,
+		data:      nil,
+		children:  append([]BER{}, children...),
+	}
+}
+
+// Encodes the tree into BER-TLV data (described in ISO/IEC 7816-4 (2005)).
+// Lengths are encoded in the shortest form.
+func (tree BER) Encode() ([]byte, error) {
+	if tree.tag == 0 && !tree.primitive {
+		return tree.encodeChildren()
+	}
+
+	tag, err := EncodeTag(tree.tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if isPrimitiveTag(tag) != tree.primitive {
+		return nil, fmt.Errorf("%w: tag %X doesn't match the node type", cardErrors.ErrInvalidFormat, tree.tag)
+	}
+
+	value := tree.data
+	if !tree.primitive {
+		value, err = tree.encodeChildren()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data := append(tag, EncodeLength(uint32(len(value)))...)
+	return append(data, value...), nil
+}
+
+func (tree BER) encodeChildren() ([]byte, error) {
+	data := []byte{}
+
+	for _, child := range tree.children {
+		encoded, err := child.Encode()
+		if err != nil {
+			return nil, fmt.Errorf("encoding tag %X: %w", child.tag, err)
+		}
+
+		data = append(data, encoded...)
+	}
+
+	return data, nil
+}
+
+// Encodes the tag according to specification given in ISO 7816-4 (5. Organization for interchange).
+// Tags are given in the same form as they are returned by ParseTag (e.g. 0x9F33 is encoded as two bytes).
+func EncodeTag(tag uint32) ([]byte, error) {
+	var data []byte
+	if tag <= 0xFF {
+		data = []byte{byte(tag)}
+	} else if tag <= 0xFFFF {
+		data = []byte{byte(tag >> 8), byte(tag)}
+	} else if tag <= 0xFFFFFF {
+		data = []byte{byte(tag >> 16), byte(tag >> 8), byte(tag)}
+	} else {
+		return nil, fmt.Errorf("%w: tag %X is too long", cardErrors.ErrInvalidFormat, tag)
+	}
+
+	multiByte := data[0]&0x1F == 0x1F
+	valid := false
+	switch len(data) {
+	case 1:
+		valid = !multiByte
+	case 2:
+		valid = multiByte && data[1]&0x80 == 0
+	case 3:
+		valid = multiByte && data[1]&0x80 != 0 && data[2]&0x80 == 0
+	}
+
+	if !valid {
+		return nil, fmt.Errorf("%w: invalid tag %X", cardErrors.ErrInvalidFormat, tag)
+	}
+
+	return data, nil
+}
+
+// Encodes the length according to specification given in ISO 7816-4 (5. Organization for interchange).
+func EncodeLength(length uint32) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	} else if length <= 0xFF {
+		return []byte{0x81, byte(length)}
+	} else if length <= 0xFFFF {
+		return []byte{0x82, byte(length >> 8), byte(length)}
+	} else if length <= 0xFFFFFF {
+		return []byte{0x83, byte(length >> 16), byte(length >> 8), byte(length)}
+	}
+
+	return []byte{0x84, byte(length >> 24), byte(length >> 16), byte(length >> 8), byte(length)}
+}
+
+// Checks the primitive bit of the first tag byte.
+func isPrimitiveTag(tag []byte) bool {
+	return tag[0]&0b100000 == 0
+}
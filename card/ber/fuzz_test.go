@@ -0,0 +1,38 @@
+package ber
+
+import (
+	"testing"
+)
+
+func FuzzParseBER(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0x71, 0x09, 0x80, 0x02, 0x30, 0x31, 0xA1, 0x03, 0x8F, 0x01, 0x41})
+	f.Add([]byte{0x9F, 0x33, 0x81, 0x01, 0x00})
+	f.Add([]byte{0x72, 0x83, 0x00, 0x00, 0x02, 0xC1, 0x00})
+	f.Add([]byte{0x71, 0x84, 0xFF, 0xFF, 0xFF, 0xFF})
+	f.Add([]byte{0x71, 0x02, 0x80, 0x00, 0x71, 0x02, 0x81, 0x00})
+	f.Add([]byte{0xBF, 0xAF, 0x55, 0x00})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tree, err := ParseBER(data)
+		if err != nil {
+			return
+		}
+
+		// Nodes with repeated tags are merged, so the encoded data can differ,
+		// but it should be valid and parse into the same tree.
+		encoded, err := tree.Encode()
+		if err != nil {
+			return
+		}
+
+		reparsed, err := ParseBER(encoded)
+		if err != nil {
+			t.Fatalf("Parsing encoded tree %X: %v", encoded, err)
+		}
+
+		if reparsed.String() != tree.String() {
+			t.Fatalf("Expected encoded tree to be parsed into\n%s\nbut got\n%s", tree, reparsed)
+		}
+	})
+}
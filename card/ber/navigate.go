@@ -0,0 +1,136 @@
+package ber
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Returns the tag of the node. Root of the parsed tree has the tag 0.
+func (tree BER) Tag() uint32 {
+	return tree.tag
+}
+
+// Checks if the node is primitive (leaf) node. Only primitive nodes contain data.
+func (tree BER) Primitive() bool {
+	return tree.primitive
+}
+
+// Returns data of the primitive node, or nil for the constructed node.
+func (tree BER) Data() []byte {
+	return tree.data
+}
+
+// Returns children of the constructed node in the order in which they appear on the card.
+func (tree BER) Children() []BER {
+	return slices.Clone(tree.children)
+}
+
+// Returns the node with the provided address composed as a list of tags.
+// Empty address returns the node itself.
+func (tree BER) Get(address ...uint32) (BER, bool) {
+	node := tree
+
+	for _, tag := range address {
+		i := slices.IndexFunc(node.children, func(child BER) bool {
+			return child.tag == tag
+		})
+
+		if i < 0 {
+			return BER{}, false
+		}
+
+		node = node.children[i]
+	}
+
+	return node, true
+}
+
+// Returns data of the node with the provided address composed as a list of tags.
+func (tree BER) Value(address ...uint32) ([]byte, error) {
+	return tree.access(address...)
+}
+
+// Returns all nodes with the given tag, at any depth of the tree, in the depth-first order.
+func (tree BER) Find(tag uint32) []BER {
+	found := []BER{}
+
+	tree.Walk(func(_ []uint32, node BER) bool {
+		if node.tag == tag {
+			found = append(found, node)
+		}
+		return true
+	})
+
+	return found
+}
+
+// Visits each node of the tree in the depth-first order, starting with the tree itself.
+// Visit function receives the address of the node (tags of all nodes on the path, excluding the root) and the node.
+// If the visit function returns false, children of the node are not visited.
+func (tree BER) Walk(visit func(address []uint32, node BER) bool) {
+	tree.walk(nil, visit)
+}
+
+func (tree BER) walk(address []uint32, visit func([]uint32, BER) bool) {
+	if !visit(slices.Clip(address), tree) {
+		return
+	}
+
+	for _, child := range tree.children {
+		child.walk(append(slices.Clip(address), child.tag), visit)
+	}
+}
+
+// Represents a node in the JSON dump of the tree.
+type jsonNode struct {
+	Tag      string     `json:"tag,omitempty"`
+	Value    string     `json:"value,omitempty"`
+	Text     string     `json:"text,omitempty"`
+	Children []jsonNode `json:"children,omitempty"`
+}
+
+// Encodes the tree into JSON. Tags and values are encoded as hex strings.
+// Values that are printable UTF-8 text are also given as text.
+// Tag of the root is omitted.
+func (tree BER) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tree.jsonNode())
+}
+
+func (tree BER) jsonNode() jsonNode {
+	node := jsonNode{}
+
+	if tree.tag != 0 {
+		node.Tag = fmt.Sprintf("%X", tree.tag)
+	}
+
+	if tree.primitive {
+		node.Value = hex.EncodeToString(tree.data)
+		if isPrintable(tree.data) {
+			node.Text = string(tree.data)
+		}
+	}
+
+	for _, child := range tree.children {
+		node.Children = append(node.Children, child.jsonNode())
+	}
+
+	return node
+}
+
+func isPrintable(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,88 @@
+package ber
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ubavic/bas-celik/card/mapper"
+)
+
+// Assigns data from the tree to the fields of the struct pointed by v.
+// Struct fields are mapped with the ber tag that holds the address of the primitive node,
+// composed of hexadecimal tags separated with dots, e.g. `ber:"71.A3.87"`.
+// Fields whose nodes are not present in the tree are not changed.
+// Data of primitive nodes that are not mapped is added to the field with the `ber:"*"` tag, if there is one.
+// See the mapper package for other tags.
+func Unmarshal(tree BER, v any) error {
+	return mapper.Map(v, "ber", treeSource{tree})
+}
+
+type treeSource struct {
+	tree BER
+}
+
+func (source treeSource) Lookup(key string) ([]byte, bool, error) {
+	address, err := ParseAddress(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	data, err := source.tree.access(address...)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	return data, true, nil
+}
+
+func (source treeSource) Unmapped(keys []string) (map[string][]byte, error) {
+	mapped := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		address, err := ParseAddress(key)
+		if err != nil {
+			return nil, err
+		}
+		mapped[FormatAddress(address)] = true
+	}
+
+	unmapped := map[string][]byte{}
+	source.tree.Walk(func(address []uint32, node BER) bool {
+		if node.primitive {
+			key := FormatAddress(address)
+			if !mapped[key] {
+				unmapped[key] = node.data
+			}
+		}
+		return true
+	})
+
+	return unmapped, nil
+}
+
+// Parses the address composed of hexadecimal tags separated with dots, e.g. "71.A3.87".
+func ParseAddress(address string) ([]uint32, error) {
+	parts := strings.Split(address, ".")
+	tags := make([]uint32, 0, len(parts))
+
+	for _, part := range parts {
+		tag, err := strconv.ParseUint(part, 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("parsing address %q: %w", address, err)
+		}
+
+		tags = append(tags, uint32(tag))
+	}
+
+	return tags, nil
+}
+
+// Formats the address as hexadecimal tags separated with dots, e.g. "71.A3.87".
+func FormatAddress(address []uint32) string {
+	parts := make([]string, len(address))
+	for i, tag := range address {
+		parts[i] = strconv.FormatUint(uint64(tag), 16)
+	}
+
+	return strings.ToUpper(strings.Join(parts, "."))
+}
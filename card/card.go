@@ -4,6 +4,7 @@
 package card
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"slices"
@@ -21,11 +22,17 @@ type Card interface {
 }
 
 // Represents a smart card with a document.
-// All types of documents that Bas Celik can read should satisfy this interface
+// All types of documents that Bas Celik can read should satisfy this interface.
+// Methods with the `Context` suffix stop communication with the card when the context is done.
+// Methods without the suffix are equivalent to calling them with `context.Background()`.
 type CardDocument interface {
 	ReadFile([]byte) ([]byte, error)
+	ReadFileContext(context.Context, []byte) ([]byte, error)
 	InitCard() error
+	InitCardContext(context.Context) error
 	ReadCard() error
+	ReadCardContext(context.Context) error
+	SetReadOptions(ReadOptions)
 	GetDocument() (doc.Document, error)
 	Test() bool
 	Atr() Atr
@@ -34,6 +41,7 @@ type CardDocument interface {
 // Represents a different types of smart card documents.
 // Each value of `CardDocumentType` is represented with a struct
 // that satisfies `CardDocument` interface.
+// Additional types can be added with RegisterCardType.
 type CardDocumentType uint8
 
 const (
@@ -42,13 +50,23 @@ const (
 	GemaltoIdDocumentCardType
 	MedicalDocumentCardType
 	VehicleDocumentCardType
+	TachographDocumentCardType
 )
 
 var ErrUnknownCard = errors.New("unknown card")
 
+// Returned when the card doesn't respond before the deadline of the context.
+var ErrReadTimeout = errors.New("card read timed out")
+
 // Detects Card Document from card's ATR
-// Ambiguous cases are solved by reading specific card content
+// Ambiguous cases are solved by reading specific card content.
+// If the ATR is unknown, each card type that can be tested is probed.
 func DetectCardDocument(sc Card) (CardDocument, error) {
+	return DetectCardDocumentContext(context.Background(), sc)
+}
+
+// Same as DetectCardDocument, but probing of the card stops when the context is done.
+func DetectCardDocumentContext(ctx context.Context, sc Card) (CardDocument, error) {
 	smartCardStatus, err := sc.Status()
 	if err != nil {
 		return nil, fmt.Errorf("reading card status %w", err)
@@ -58,40 +76,110 @@ func DetectCardDocument(sc Card) (CardDocument, error) {
 
 	possibleCardTypes := DetectCardDocumentByAtr(atr)
 
+	if !IsAtrKnown(atr) {
+		possibleCardTypes = probedCardTypes()
+	}
+
+	setDetectedCardTypes(map[CardDocument]CardDocumentType{})
+
+	// Cards are tested through the wrapper, so that the probing can be canceled.
+	probe := contextCard{ctx: ctx, Card: sc}
+
 	for _, cardType := range possibleCardTypes {
-		switch cardType {
-		case ApolloIdDocumentCardType:
-			card := &Apollo{atr: atr, smartCard: sc}
+		card := probeCardDocument(cardType, atr, sc, probe)
+		if card != nil {
+			setDetectedCardTypes(map[CardDocument]CardDocumentType{card: cardType})
 			return card, nil
-		case GemaltoIdDocumentCardType:
-			card := Gemalto{atr: atr, smartCard: sc}
-			if card.Test() {
-				return &card, nil
-			}
-		case VehicleDocumentCardType:
-			card := VehicleCard{atr: atr, smartCard: sc}
-			if card.Test() {
-				return &card, nil
-			}
-		case MedicalDocumentCardType:
-			card := MedicalCard{atr: atr, smartCard: sc}
-			if card.Test() {
-				return &card, nil
-			}
-		default:
-			card := &UnknownDocumentCard{atr: atr, smartCard: sc}
-			return card, ErrUnknownCard
 		}
+
+		if ctx.Err() != nil {
+			return nil, contextError(ctx.Err())
+		}
+	}
+
+	if !IsAtrKnown(atr) {
+		card := &UnknownDocumentCard{atr: atr, smartCard: sc}
+		return card, ErrUnknownCard
+	}
+
+	return nil, errors.New("unexpected card type")
+}
+
+// Detects all documents stored on the card.
+// Unlike DetectCardDocument, every possible card type is probed,
+// so a card with multiple applications gives a document for each application.
+// Returned documents are sorted by their card type.
+func DetectCardDocuments(sc Card) ([]CardDocument, error) {
+	return DetectCardDocumentsContext(context.Background(), sc)
+}
+
+// Same as DetectCardDocuments, but probing of the card stops when the context is done.
+func DetectCardDocumentsContext(ctx context.Context, sc Card) ([]CardDocument, error) {
+	smartCardStatus, err := sc.Status()
+	if err != nil {
+		return nil, fmt.Errorf("reading card status %w", err)
+	}
+
+	atr := Atr(smartCardStatus.Atr)
+
+	possibleCardTypes := slices.Clone(DetectCardDocumentByAtr(atr))
+	if !IsAtrKnown(atr) {
+		possibleCardTypes = probedCardTypes()
+	}
+
+	slices.Sort(possibleCardTypes)
+
+	setDetectedCardTypes(map[CardDocument]CardDocumentType{})
+
+	probe := contextCard{ctx: ctx, Card: sc}
+	cards := []CardDocument{}
+	detected := map[CardDocument]CardDocumentType{}
+
+	for _, cardType := range possibleCardTypes {
+		card := probeCardDocument(cardType, atr, sc, probe)
+		if card != nil {
+			cards = append(cards, card)
+			detected[card] = cardType
+		}
+
+		if ctx.Err() != nil {
+			return nil, contextError(ctx.Err())
+		}
+	}
+
+	if len(cards) > 0 {
+		setDetectedCardTypes(detected)
+		return cards, nil
+	}
+
+	if !IsAtrKnown(atr) {
+		card := &UnknownDocumentCard{atr: atr, smartCard: sc}
+		return []CardDocument{card}, ErrUnknownCard
 	}
 
 	return nil, errors.New("unexpected card type")
 }
 
+// Returns the card document of the given type if the card passes its test, and nil otherwise.
+// The card is tested through the probe, but the returned document communicates directly with sc.
+func probeCardDocument(documentType CardDocumentType, atr Atr, sc, probe Card) CardDocument {
+	cardType, ok := lookupCardType(documentType)
+	if !ok {
+		return nil
+	}
+
+	if !cardType.New(atr, probe).Test() {
+		return nil
+	}
+
+	return cardType.New(atr, sc)
+}
+
 // Reads binary data from the card starting from the specified offset and with the specified length.
-func read(card Card, offset, length uint) ([]byte, error) {
+func read(ctx context.Context, card Card, offset, length uint) ([]byte, error) {
 	readSize := min(length, 0xFF)
 	apu := buildAPDU(0x00, 0xB0, byte((0xFF00&offset)>>8), byte(offset&0xFF), nil, readSize)
-	rsp, err := card.Transmit(apu)
+	rsp, err := transmit(ctx, card, apu)
 	if err != nil {
 		return nil, fmt.Errorf("reading binary: %w", err)
 	}
@@ -103,6 +191,42 @@ func read(card Card, offset, length uint) ([]byte, error) {
 	return rsp[:len(rsp)-2], nil
 }
 
+// Sends the command to the card if the context is not done.
+// The context is checked before and after the transmission, so no command reaches the card after
+// the function returns. Transmission itself is not interrupted here: callers that own the connection
+// unblock a stuck reader by disconnecting the card when the context is done (see context.AfterFunc).
+func transmit(ctx context.Context, card Card, apdu []byte) ([]byte, error) {
+	if ctx.Err() != nil {
+		return nil, contextError(ctx.Err())
+	}
+
+	rsp, err := card.Transmit(apdu)
+	if ctx.Err() != nil {
+		return nil, contextError(ctx.Err())
+	}
+
+	return rsp, err
+}
+
+// Replaces the deadline error with ErrReadTimeout.
+func contextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrReadTimeout, err)
+	}
+
+	return err
+}
+
+// Wraps the card so that every transmission is bound to the context.
+type contextCard struct {
+	ctx context.Context
+	Card
+}
+
+func (card contextCard) Transmit(apdu []byte) ([]byte, error) {
+	return transmit(card.ctx, card.Card, apdu)
+}
+
 // Checks if the card response indicates no error.
 func responseOK(rsp []byte) bool {
 	if len(rsp) < 2 {
@@ -1,8 +1,17 @@
 package card
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"testing"
+	"time"
+
+	"github.com/ebfe/scard"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
 )
 
 func Test_responseOK(t *testing.T) {
@@ -30,3 +39,246 @@ func Test_responseOK(t *testing.T) {
 		)
 	}
 }
+
+// Virtual card that responds after the given delay.
+type slowCard struct {
+	delay time.Duration
+}
+
+func (card *slowCard) Status() (*scard.CardStatus, error) {
+	return &scard.CardStatus{}, nil
+}
+
+func (card *slowCard) Transmit(_ []byte) ([]byte, error) {
+	time.Sleep(card.delay)
+	return []byte{0x90, 0x00}, nil
+}
+
+func Test_transmit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := transmit(ctx, &slowCard{delay: 50 * time.Millisecond}, []byte{0x00})
+	if !errors.Is(err, ErrReadTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected timeout error, but got %v", err)
+	}
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = transmit(canceledCtx, &slowCard{}, []byte{0x00})
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrReadTimeout) {
+		t.Errorf("Expected cancellation error, but got %v", err)
+	}
+
+	rsp, err := transmit(context.Background(), &slowCard{}, []byte{0x00})
+	if err != nil || !responseOK(rsp) {
+		t.Errorf("Expected response, but got %v, %v", rsp, err)
+	}
+}
+
+func Test_ReadCardContextTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	card := MedicalCard{smartCard: &slowCard{delay: 50 * time.Millisecond}}
+	err := card.ReadCardContext(ctx)
+	if !errors.Is(err, ErrReadTimeout) {
+		t.Errorf("Expected timeout error, but got %v", err)
+	}
+}
+
+// Virtual card that returns a 24 byte file (filled with empty TLV records), at most 10 bytes at a time.
+type medicalFileCard struct{}
+
+func (card *medicalFileCard) Status() (*scard.CardStatus, error) {
+	return &scard.CardStatus{}, nil
+}
+
+func (card *medicalFileCard) Transmit(apdu []byte) ([]byte, error) {
+	if apdu[1] == 0xB0 && apdu[3] == 0 {
+		return []byte{0x00, 0x00, 0x18, 0x00, 0x90, 0x00}, nil
+	}
+
+	length := min(int(apdu[4]), 10)
+	return append(make([]byte, length), 0x90, 0x00), nil
+}
+
+func Test_ReadProgress(t *testing.T) {
+	progress := []Progress{}
+
+	card := MedicalCard{smartCard: &medicalFileCard{}}
+	card.SetReadOptions(ReadOptions{Progress: func(p Progress) {
+		progress = append(progress, p)
+	}})
+
+	err := card.ReadCard()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	// Each file is reported once before reading and three times during reading.
+	if len(progress) != 16 {
+		t.Fatalf("Expected 16 progress reports, but got %d", len(progress))
+	}
+
+	expected := []Progress{
+		{File: 1, FileCount: 4, BytesRead: 0, FileLength: 24},
+		{File: 1, FileCount: 4, BytesRead: 10, FileLength: 24},
+		{File: 1, FileCount: 4, BytesRead: 20, FileLength: 24},
+		{File: 1, FileCount: 4, BytesRead: 24, FileLength: 24},
+		{File: 2, FileCount: 4, BytesRead: 0, FileLength: 24},
+	}
+
+	for i, p := range expected {
+		if progress[i] != p {
+			t.Errorf("Expected progress %v, but got %v", p, progress[i])
+		}
+	}
+
+	last := progress[len(progress)-1]
+	if last.File != 4 || last.Fraction() != 1 {
+		t.Errorf("Expected read to be completed, but got %v", last)
+	}
+
+	// Files read outside of ReadCard (e.g. by Test) are not counted.
+	_, err = card.ReadFile(MED_DOCUMENT_FILE_LOC)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if len(progress) != 16 || card.file != 4 {
+		t.Errorf("Expected file read after the card read not to be tracked, but got file %d", card.file)
+	}
+}
+
+func Test_ReadParts(t *testing.T) {
+	fileCount := 0
+
+	card := MedicalCard{smartCard: &medicalFileCard{}}
+	card.SetReadOptions(ReadOptions{
+		Parts: PartPersonal | PartAdministrative | PartPortrait,
+		Progress: func(p Progress) {
+			fileCount = p.FileCount
+		},
+	})
+
+	err := card.ReadCard()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if card.medicalDocumentFile != nil || card.variablePersonalFile != nil {
+		t.Errorf("Expected skipped files to be empty")
+	}
+
+	if card.fixedPersonalFile == nil || card.variableAdminFile == nil {
+		t.Errorf("Expected selected files to be read")
+	}
+
+	if fileCount != 2 {
+		t.Errorf("Expected 2 files to be read, but got %d", fileCount)
+	}
+
+	_, err = card.GetDocument()
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+// Virtual card with the ID and the medical application.
+// Both applications have a single file with the medical insurer name.
+type multiApplicationCard struct {
+	selected bool
+}
+
+func (card *multiApplicationCard) Status() (*scard.CardStatus, error) {
+	return &scard.CardStatus{Atr: GEMALTO_ATR_2}, nil
+}
+
+func (card *multiApplicationCard) Transmit(apdu []byte) ([]byte, error) {
+	name, _, _ := transform.Bytes(unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder(), []byte("Републички фонд за здравствено осигурање"))
+	file := append([]byte{0x11, 0x06, byte(len(name)), 0x00}, name...)
+	file = append([]byte{0x00, 0x00, byte(len(file)), 0x00}, file...)
+
+	switch {
+	case apdu[1] == 0xA4 && apdu[2] == 0x04:
+		card.selected = bytes.Contains(apdu, GEMALTO_ID_AID) || bytes.Contains(apdu, MEDICAL_AID)
+		if !card.selected {
+			return []byte{0x6A, 0x82}, nil
+		}
+	case apdu[1] == 0xB0 && card.selected:
+		offset := min(int(apdu[2])<<8|int(apdu[3]), len(file))
+		end := min(offset+int(apdu[4]), len(file))
+		return append(slices.Clone(file[offset:end]), 0x90, 0x00), nil
+	case !card.selected:
+		return []byte{0x6A, 0x82}, nil
+	}
+
+	return []byte{0x90, 0x00}, nil
+}
+
+func Test_DetectCardDocuments(t *testing.T) {
+	cards, err := DetectCardDocuments(&multiApplicationCard{})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if len(cards) != 2 {
+		t.Fatalf("Expected 2 documents, but got %d", len(cards))
+	}
+
+	if _, ok := cards[0].(*Gemalto); !ok {
+		t.Errorf("Expected ID card, but got %T", cards[0])
+	}
+
+	if _, ok := cards[1].(*MedicalCard); !ok {
+		t.Errorf("Expected medical card, but got %T", cards[1])
+	}
+
+	card, err := DetectCardDocument(&multiApplicationCard{})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if _, ok := card.(*Gemalto); !ok {
+		t.Errorf("Expected ID card, but got %T", card)
+	}
+}
+
+func Test_ShouldReportAtr(t *testing.T) {
+	unknownAtr := Atr{0x3B, 0x00}
+
+	testCases := []struct {
+		name           string
+		cardDoc        CardDocument
+		documentType   CardDocumentType
+		expectedResult bool
+	}{
+		{name: "known ATR", cardDoc: &Gemalto{atr: GEMALTO_ATR_2}, documentType: GemaltoIdDocumentCardType, expectedResult: false},
+		{name: "probed ID card", cardDoc: &Gemalto{atr: unknownAtr}, documentType: GemaltoIdDocumentCardType, expectedResult: true},
+		{name: "probed vehicle card", cardDoc: &VehicleCard{atr: unknownAtr}, documentType: VehicleDocumentCardType, expectedResult: true},
+		{name: "probed tachograph card", cardDoc: &TachographCard{atr: unknownAtr}, documentType: TachographDocumentCardType, expectedResult: false},
+		{name: "unknown card", cardDoc: &UnknownDocumentCard{atr: unknownAtr}, expectedResult: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			detected := map[CardDocument]CardDocumentType{}
+			if testCase.documentType != UnknownDocumentCardType {
+				detected[testCase.cardDoc] = testCase.documentType
+			}
+			setDetectedCardTypes(detected)
+
+			if ShouldReportAtr(testCase.cardDoc) != testCase.expectedResult {
+				t.Errorf("Expected %v", testCase.expectedResult)
+			}
+		})
+	}
+
+	// Documents that are not returned by the latest detection are not reported.
+	setDetectedCardTypes(map[CardDocument]CardDocumentType{})
+	if ShouldReportAtr(&Gemalto{atr: unknownAtr}) {
+		t.Errorf("Expected document that isn't detected not to be reported")
+	}
+}
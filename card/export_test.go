@@ -0,0 +1,17 @@
+package card
+
+import "testing"
+
+// Restores the ATR table when the test finishes, so that entries added by the test don't affect other tests.
+func RestoreAtrTable(t testing.TB) {
+	registryMu.RLock()
+	table := atrTable
+	registryMu.RUnlock()
+
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+
+		atrTable = table
+	})
+}
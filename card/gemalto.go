@@ -1,6 +1,7 @@
 package card
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -36,8 +37,16 @@ var GEMALTO_ATR_4 = Atr([]byte{
 	0x32, 0x56, 0x30, 0x0D, 0x0A, 0x6C,
 })
 
+// Application identifiers of the ID card application.
+// Cards are initialized by selecting the first application that responds.
+var GEMALTO_ID_AID = []byte{0xF3, 0x81, 0x00, 0x00, 0x02, 0x53, 0x45, 0x52, 0x49, 0x44, 0x01}
+
+var GEMALTO_IF_AID = []byte{0xF3, 0x81, 0x00, 0x00, 0x02, 0x53, 0x45, 0x52, 0x49, 0x46, 0x01}
+var GEMALTO_RP_AID = []byte{0xF3, 0x81, 0x00, 0x00, 0x02, 0x53, 0x45, 0x52, 0x52, 0x50, 0x01}
+
 // Gemalto represents ID cards based with Gemalto Java OS. Gemalto replaced Apollo cards around 2014.
 type Gemalto struct {
+	readTracker
 	atr           Atr
 	smartCard     Card
 	documentFile  []byte
@@ -47,9 +56,12 @@ type Gemalto struct {
 }
 
 func (card *Gemalto) InitCard() error {
-	data := []byte{0xF3, 0x81, 0x00, 0x00, 0x02, 0x53, 0x45, 0x52, 0x49, 0x44, 0x01}
-	apu := buildAPDU(0x00, 0xA4, 0x04, 0x00, data, 0)
-	rsp, err := card.smartCard.Transmit(apu)
+	return card.InitCardContext(context.Background())
+}
+
+func (card *Gemalto) InitCardContext(ctx context.Context) error {
+	apu := buildAPDU(0x00, 0xA4, 0x04, 0x00, GEMALTO_ID_AID, 0)
+	rsp, err := transmit(ctx, card.smartCard, apu)
 	if err != nil {
 		return fmt.Errorf("initializing ID card: %w", err)
 	}
@@ -58,9 +70,8 @@ func (card *Gemalto) InitCard() error {
 		return nil
 	}
 
-	data = []byte{0xF3, 0x81, 0x00, 0x00, 0x02, 0x53, 0x45, 0x52, 0x49, 0x46, 0x01}
-	apu = buildAPDU(0x00, 0xA4, 0x04, 0x00, data, 0)
-	rsp, err = card.smartCard.Transmit(apu)
+	apu = buildAPDU(0x00, 0xA4, 0x04, 0x00, GEMALTO_IF_AID, 0)
+	rsp, err = transmit(ctx, card.smartCard, apu)
 	if err != nil {
 		return fmt.Errorf("initializing IF card: %w", err)
 	}
@@ -69,9 +80,8 @@ func (card *Gemalto) InitCard() error {
 		return nil
 	}
 
-	data = []byte{0xF3, 0x81, 0x00, 0x00, 0x02, 0x53, 0x45, 0x52, 0x52, 0x50, 0x01}
-	apu = buildAPDU(0x00, 0xA4, 0x04, 0x00, data, 0)
-	rsp, err = card.smartCard.Transmit(apu)
+	apu = buildAPDU(0x00, 0xA4, 0x04, 0x00, GEMALTO_RP_AID, 0)
+	rsp, err = transmit(ctx, card.smartCard, apu)
 	if err != nil {
 		return fmt.Errorf("initializing RP card: %w", err)
 	}
@@ -84,29 +94,44 @@ func (card *Gemalto) InitCard() error {
 }
 
 func (card *Gemalto) ReadCard() error {
+	return card.ReadCardContext(context.Background())
+}
+
+func (card *Gemalto) ReadCardContext(ctx context.Context) error {
+	card.startRead(card.countWanted(PartDocument, PartPersonal, PartResidence, PartPortrait))
+	defer card.endRead()
+
 	var err error
 
-	card.documentFile, err = card.ReadFile(ID_DOCUMENT_FILE_LOC)
-	if err != nil {
-		return fmt.Errorf("reading document file: %w", err)
+	if card.wants(PartDocument) {
+		card.documentFile, err = card.ReadFileContext(ctx, ID_DOCUMENT_FILE_LOC)
+		if err != nil {
+			return fmt.Errorf("reading document file: %w", err)
+		}
 	}
 
-	card.personalFile, err = card.ReadFile(ID_PERSONAL_FILE_LOC)
-	if err != nil {
-		return fmt.Errorf("reading personal file: %w", err)
+	if card.wants(PartPersonal) {
+		card.personalFile, err = card.ReadFileContext(ctx, ID_PERSONAL_FILE_LOC)
+		if err != nil {
+			return fmt.Errorf("reading personal file: %w", err)
+		}
 	}
 
-	card.residenceFile, err = card.ReadFile(ID_RESIDENCE_FILE_LOC)
-	if err != nil {
-		return fmt.Errorf("reading residence file: %w", err)
+	if card.wants(PartResidence) {
+		card.residenceFile, err = card.ReadFileContext(ctx, ID_RESIDENCE_FILE_LOC)
+		if err != nil {
+			return fmt.Errorf("reading residence file: %w", err)
+		}
 	}
 
-	rsp, err := card.ReadFile(ID_PHOTO_FILE_LOC)
-	if err != nil {
-		return fmt.Errorf("reading photo file: %w", err)
-	}
+	if card.wants(PartPortrait) {
+		rsp, err := card.ReadFileContext(ctx, ID_PHOTO_FILE_LOC)
+		if err != nil {
+			return fmt.Errorf("reading photo file: %w", err)
+		}
 
-	card.photoFile = trim4b(rsp)
+		card.photoFile = trim4b(rsp)
+	}
 
 	return nil
 }
@@ -114,24 +139,32 @@ func (card *Gemalto) ReadCard() error {
 func (card *Gemalto) GetDocument() (document.Document, error) {
 	doc := document.IdDocument{}
 
-	err := parseIdDocumentFile(card.documentFile, &doc)
-	if err != nil {
-		return nil, fmt.Errorf("parsing document file: %w", err)
+	if card.documentFile != nil {
+		err := parseIdFile(card.documentFile, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing document file: %w", err)
+		}
 	}
 
-	err = parseIdPersonalFile(card.personalFile, &doc)
-	if err != nil {
-		return nil, fmt.Errorf("parsing personal file: %w", err)
+	if card.personalFile != nil {
+		err := parseIdFile(card.personalFile, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing personal file: %w", err)
+		}
 	}
 
-	err = parseIdResidenceFile(card.residenceFile, &doc)
-	if err != nil {
-		return nil, fmt.Errorf("parsing residence file: %w", err)
+	if card.residenceFile != nil {
+		err := parseIdFile(card.residenceFile, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing residence file: %w", err)
+		}
 	}
 
-	err = parseAndAssignIdPhotoFile(card.photoFile, &doc)
-	if err != nil {
-		return nil, fmt.Errorf("parsing photo file: %w", err)
+	if card.photoFile != nil {
+		err := parseAndAssignIdPhotoFile(card.photoFile, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing photo file: %w", err)
+		}
 	}
 
 	return &doc, nil
@@ -142,14 +175,19 @@ func (card *Gemalto) Atr() Atr {
 }
 
 func (card *Gemalto) ReadFile(name []byte) ([]byte, error) {
+	return card.ReadFileContext(context.Background(), name)
+}
+
+func (card *Gemalto) ReadFileContext(ctx context.Context, name []byte) ([]byte, error) {
+	card.startFile()
 	output := make([]byte, 0)
 
-	_, err := card.selectFile(name, 4)
+	_, err := card.selectFile(ctx, name, 4)
 	if err != nil {
 		return nil, fmt.Errorf("selecting file: %w", err)
 	}
 
-	data, err := read(card.smartCard, 0, 4)
+	data, err := read(ctx, card.smartCard, 0, 4)
 	if err != nil {
 		return nil, fmt.Errorf("reading file header: %w", err)
 	}
@@ -160,13 +198,17 @@ func (card *Gemalto) ReadFile(name []byte) ([]byte, error) {
 	}
 	length := uint(binary.LittleEndian.Uint16(data[2:]))
 
+	fileLength := length
+	card.reportProgress(0, fileLength)
+
 	for length > 0 {
-		data, err := read(card.smartCard, offset, length)
+		data, err := read(ctx, card.smartCard, offset, length)
 		if err != nil {
 			return nil, fmt.Errorf("reading file: %w", err)
 		}
 
 		output = append(output, data...)
+		card.reportProgress(uint(len(output)), fileLength)
 
 		offset += uint(len(data))
 		length -= uint(len(data))
@@ -175,9 +217,9 @@ func (card *Gemalto) ReadFile(name []byte) ([]byte, error) {
 	return output, nil
 }
 
-func (card *Gemalto) selectFile(name []byte, ne uint) ([]byte, error) {
+func (card *Gemalto) selectFile(ctx context.Context, name []byte, ne uint) ([]byte, error) {
 	apu := buildAPDU(0x00, 0xA4, 0x08, 0x00, name, ne)
-	rsp, err := card.smartCard.Transmit(apu)
+	rsp, err := transmit(ctx, card.smartCard, apu)
 	if err != nil {
 		return nil, fmt.Errorf("selecting file: %w", err)
 	}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/ubavic/bas-celik/card/tlv"
 	"github.com/ubavic/bas-celik/document"
-	"github.com/ubavic/bas-celik/localization"
 )
 
 // Location of the file with document data.
@@ -22,67 +21,14 @@ var ID_RESIDENCE_FILE_LOC = []byte{0x0F, 0x04}
 // Location of the the portrait. Portrait is encoded as JPEG.
 var ID_PHOTO_FILE_LOC = []byte{0x0F, 0x06}
 
-func parseIdDocumentFile(data []byte, doc *document.IdDocument) error {
+// Parses the document, personal or residence file and assigns its fields to the document.
+func parseIdFile(data []byte, doc *document.IdDocument) error {
 	fields, err := tlv.ParseTLV(data)
 	if err != nil {
 		return err
 	}
-	tlv.AssignField(fields, 1546, &doc.DocRegNo)
-	tlv.AssignField(fields, 1547, &doc.DocumentType)
-	tlv.AssignField(fields, 1548, &doc.DocumentSerialNumber)
-	tlv.AssignField(fields, 1549, &doc.IssuingDate)
-	tlv.AssignField(fields, 1550, &doc.ExpiryDate)
-	tlv.AssignField(fields, 1551, &doc.IssuingAuthority)
-	tlv.AssignField(fields, 1681, &doc.ChipSerialNumber)
-	tlv.AssignField(fields, 1682, &doc.DocumentName)
-	localization.FormatDate(&doc.IssuingDate)
-	localization.FormatDate(&doc.ExpiryDate)
 
-	return nil
-}
-
-func parseIdPersonalFile(data []byte, doc *document.IdDocument) error {
-	fields, err := tlv.ParseTLV(data)
-	if err != nil {
-		return err
-	}
-
-	tlv.AssignField(fields, 1558, &doc.PersonalNumber)
-	tlv.AssignField(fields, 1559, &doc.Surname)
-	tlv.AssignField(fields, 1560, &doc.GivenName)
-	tlv.AssignField(fields, 1561, &doc.ParentGivenName)
-	tlv.AssignField(fields, 1562, &doc.Sex)
-	tlv.AssignField(fields, 1563, &doc.PlaceOfBirth)
-	tlv.AssignField(fields, 1564, &doc.CommunityOfBirth)
-	tlv.AssignField(fields, 1565, &doc.StateOfBirth)
-	tlv.AssignField(fields, 1566, &doc.DateOfBirth)
-	tlv.AssignField(fields, 1567, &doc.StateOfBirthCode)
-	tlv.AssignField(fields, 1583, &doc.NationalityFull)
-	tlv.AssignField(fields, 1683, &doc.PurposeOfStay)
-	tlv.AssignField(fields, 1684, &doc.ENote)
-	localization.FormatDate(&doc.DateOfBirth)
-
-	return nil
-}
-
-func parseIdResidenceFile(data []byte, doc *document.IdDocument) error {
-	fields, err := tlv.ParseTLV(data)
-	if err != nil {
-		return err
-	}
-	tlv.AssignField(fields, 1568, &doc.State)
-	tlv.AssignField(fields, 1569, &doc.Community)
-	tlv.AssignField(fields, 1570, &doc.Place)
-	tlv.AssignField(fields, 1571, &doc.Street)
-	tlv.AssignField(fields, 1572, &doc.HouseNumber)
-	tlv.AssignField(fields, 1573, &doc.HouseLetter)
-	tlv.AssignField(fields, 1574, &doc.Entrance)
-	tlv.AssignField(fields, 1575, &doc.Floor)
-	tlv.AssignField(fields, 1578, &doc.ApartmentNumber)
-	tlv.AssignField(fields, 1580, &doc.AddressDate)
-	localization.FormatDate(&doc.AddressDate)
-
-	return nil
+	return tlv.Unmarshal(fields, doc)
 }
 
 func parseAndAssignIdPhotoFile(data []byte, doc *document.IdDocument) error {
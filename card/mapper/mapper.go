@@ -0,0 +1,243 @@
+// Package mapper fills document structs from parsed card files, based on struct tags.
+//
+// The source tag (for example `tlv:"1558"` or `ber:"71.A3.87"`) holds the key of the value in the parsed file.
+// Alternative keys are separated with "|", and the first non-empty value is used.
+// The value can be decoded with the enc tag and formatted with the date tag:
+//
+//	enc:"utf16le"  value is UTF-16 encoded, little endian (used on medical cards). Invalid values are empty
+//	date:"dmy"     value is a date in the DDMMYYYY format
+//	date:"ymd"     value is a date in the YYYYMMDD format
+//
+// Supported field types are string, bool and document.Date. Bool fields are true when the value is "1".
+// Date fields require the date tag, and hold both the parsed date and the formatted text.
+// Fields without the source tag, and fields whose values are not present, are not changed.
+// That way, a single struct can be filled from several files.
+//
+// A field of the map[string][]byte type (e.g. document.Extra) with the source tag "*"
+// receives values that are not mapped by any other field, keyed by their tags.
+// Values are added to the map, so the map can collect values from several files.
+package mapper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/ubavic/bas-celik/document"
+	"github.com/ubavic/bas-celik/localization"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Returned when the struct or its tags can't be used for mapping.
+var ErrInvalidMapping = errors.New("invalid mapping")
+
+// Source of the values, such as a parsed file.
+type Source interface {
+	// Returns the value for the key from the source tag.
+	// Missing values are reported with false. Error is returned only if the key is malformed.
+	Lookup(key string) ([]byte, bool, error)
+	// Returns values whose keys are not among the provided keys.
+	Unmapped(keys []string) (map[string][]byte, error)
+}
+
+// Key of the field that receives unmapped values.
+const extraKey = "*"
+
+var extraType = reflect.TypeFor[map[string][]byte]()
+
+var dateType = reflect.TypeFor[document.Date]()
+
+// Assigns values from the source to the fields of the struct pointed by v.
+// The source tag name is provided with tagName.
+func Map(v any, tagName string, source Source) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Pointer || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: expected pointer to struct, got %T", ErrInvalidMapping, v)
+	}
+
+	target = target.Elem()
+	targetType := target.Type()
+
+	mappedKeys := []string{}
+	extraField := -1
+
+	for i := range targetType.NumField() {
+		field := targetType.Field(i)
+		keys, ok := field.Tag.Lookup(tagName)
+		if !ok || !field.IsExported() {
+			continue
+		}
+
+		if keys == extraKey {
+			if !field.Type.ConvertibleTo(extraType) {
+				return fmt.Errorf("%w: field %s can't hold unmapped values", ErrInvalidMapping, field.Name)
+			}
+			extraField = i
+			continue
+		}
+
+		mappedKeys = append(mappedKeys, splitKeys(keys)...)
+
+		value, found, err := lookupFirst(keys, source)
+		if err != nil {
+			return fmt.Errorf("%w: field %s: %w", ErrInvalidMapping, field.Name, err)
+		}
+
+		if !found {
+			continue
+		}
+
+		err = assign(target.Field(i), field, value)
+		if err != nil {
+			return fmt.Errorf("assigning field %s: %w", field.Name, err)
+		}
+	}
+
+	if extraField >= 0 {
+		err := assignExtra(target.Field(extraField), mappedKeys, source)
+		if err != nil {
+			return fmt.Errorf("assigning unmapped values: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Adds unmapped values from the source to the map.
+func assignExtra(target reflect.Value, mappedKeys []string, source Source) error {
+	unmapped, err := source.Unmapped(mappedKeys)
+	if err != nil {
+		return err
+	}
+
+	if len(unmapped) == 0 {
+		return nil
+	}
+
+	if target.IsNil() {
+		target.Set(reflect.MakeMap(target.Type()))
+	}
+
+	for key, value := range unmapped {
+		target.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(value))
+	}
+
+	return nil
+}
+
+func splitKeys(keys string) []string {
+	split := strings.Split(keys, "|")
+	for i := range split {
+		split[i] = strings.TrimSpace(split[i])
+	}
+
+	return split
+}
+
+// Returns the first non-empty value among the keys separated with "|".
+// If all present values are empty, the empty value is returned.
+func lookupFirst(keys string, source Source) ([]byte, bool, error) {
+	var value []byte
+	found := false
+
+	for _, key := range splitKeys(keys) {
+		v, ok, err := source.Lookup(key)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if len(v) > 0 {
+			return v, true, nil
+		}
+
+		value, found = v, true
+	}
+
+	return value, found, nil
+}
+
+func assign(target reflect.Value, field reflect.StructField, value []byte) error {
+	if target.Type() == dateType {
+		str, err := decode(value, field.Tag.Get("enc"))
+		if err != nil {
+			return err
+		}
+
+		date, err := parseDate(str, field.Tag.Get("date"))
+		if err != nil {
+			return err
+		}
+
+		target.Set(reflect.ValueOf(date))
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.String:
+		str, err := decode(value, field.Tag.Get("enc"))
+		if err != nil {
+			return err
+		}
+
+		str, err = formatDate(str, field.Tag.Get("date"))
+		if err != nil {
+			return err
+		}
+
+		target.SetString(str)
+	case reflect.Bool:
+		target.SetBool(len(value) == 1 && value[0] == 0x31)
+	default:
+		return fmt.Errorf("%w: unsupported type %s", ErrInvalidMapping, target.Type())
+	}
+
+	return nil
+}
+
+func decode(value []byte, encoding string) (string, error) {
+	switch encoding {
+	case "":
+		return string(value), nil
+	case "utf16le":
+		decoder := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder()
+		utf8, _, err := transform.Bytes(decoder, value)
+		if err != nil {
+			// Damaged value is left empty, so that it doesn't prevent reading of the whole document.
+			return "", nil
+		}
+		return string(utf8), nil
+	default:
+		return "", fmt.Errorf("%w: unknown encoding %q", ErrInvalidMapping, encoding)
+	}
+}
+
+func formatDate(value, format string) (string, error) {
+	switch format {
+	case "":
+	case "dmy":
+		localization.FormatDate(&value)
+	case "ymd":
+		localization.FormatDateYMD(&value)
+	default:
+		return "", fmt.Errorf("%w: unknown date format %q", ErrInvalidMapping, format)
+	}
+
+	return value, nil
+}
+
+func parseDate(value, format string) (document.Date, error) {
+	switch format {
+	case "dmy":
+		return document.ParseDateDMY(value), nil
+	case "ymd":
+		return document.ParseDateYMD(value), nil
+	default:
+		return document.Date{}, fmt.Errorf("%w: unknown date format %q", ErrInvalidMapping, format)
+	}
+}
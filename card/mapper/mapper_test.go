@@ -0,0 +1,242 @@
+package mapper
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ubavic/bas-celik/document"
+)
+
+type testDocument struct {
+	Name      string `test:"name" enc:"utf16le"`
+	Date      string `test:"date" date:"dmy"`
+	OtherDate string `test:"other" date:"ymd"`
+	Number    string `test:"missing|number"`
+	Valid     bool   `test:"valid"`
+	Untouched string `test:"missing"`
+	Skipped   string
+	Issued    document.Date `test:"issued" date:"ymd"`
+}
+
+type testSource map[string][]byte
+
+func (source testSource) Lookup(key string) ([]byte, bool, error) {
+	if key == "bad" {
+		return nil, false, errBadKey
+	}
+
+	value, ok := source[key]
+	return value, ok, nil
+}
+
+func (source testSource) Unmapped(keys []string) (map[string][]byte, error) {
+	unmapped := map[string][]byte{}
+	for key, value := range source {
+		if !slices.Contains(keys, key) {
+			unmapped[key] = value
+		}
+	}
+
+	return unmapped, nil
+}
+
+var errBadKey = errors.New("bad key")
+
+func Test_Map(t *testing.T) {
+	values := map[string][]byte{
+		"name":   {0x1F, 0x04, 0x35, 0x04, 0x40, 0x04, 0x30, 0x04},
+		"date":   []byte("01022023"),
+		"other":  []byte("20230201"),
+		"number": []byte("42"),
+		"valid":  []byte("1"),
+		"issued": []byte("20200115"),
+	}
+
+	doc := testDocument{Untouched: "old", Skipped: "old"}
+	err := Map(&doc, "test", testSource(values))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	expected := testDocument{
+		Name:      "Пера",
+		Date:      "01.02.2023.",
+		OtherDate: "01.02.2023",
+		Number:    "42",
+		Valid:     true,
+		Untouched: "old",
+		Skipped:   "old",
+		Issued: document.Date{
+			Time: time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC),
+			Text: "15.01.2020",
+		},
+	}
+
+	if doc != expected {
+		t.Errorf("Expected %+v, but got %+v", expected, doc)
+	}
+}
+
+func Test_MapDamagedValue(t *testing.T) {
+	doc := testDocument{}
+	values := map[string][]byte{"name": {0x1F, 0x04, 0x35}, "number": []byte("42")}
+
+	err := Map(&doc, "test", testSource(values))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if doc.Number != "42" {
+		t.Errorf("Expected other fields to be mapped, but got %+v", doc)
+	}
+}
+
+func Test_decode(t *testing.T) {
+	// Some raw bytes from different medical cards
+	testCases := []struct {
+		data, expectedData string
+	}{
+		{
+			"200435043f04430431043b04380447043a043804200044043e043d04340420003704300420003704340440043004320441044204320435043d043e0420003e044104380433044304400430045a043504",
+			"Републички фонд за здравствено осигурање",
+		},
+		{
+			"210440043104380458043004",
+			"Србија",
+		},
+		{
+			"110415041e041304200410041404",
+			"БЕОГРАД",
+		},
+		{
+			"170430043f043e0441043b0435043d0438042000430420003f044004380432044004350434043d043e043c04200034044004430448044204320443042c00200034044004430433043e043c0420003f044004300432043d043e043c0420003b043804460443042c0020003a043e04340420003f0440043504340443043704350442043d0438043a0430042c00200046043804320438043b043d04300420003b0438044604300420003d043004200041043b04430436043104380420004304200032043e045804410446043804",
+			"Запослени у привредном друштву, другом правном лицу, код предузетника, цивилна лица на служби у војсци",
+		},
+		{
+			"110443045f04350442042000200435043f04430431043b0438043a0435042000210440043104380458043504",
+			"Буџет Републике Србије",
+		},
+		{"", ""},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			data, err := hex.DecodeString(testCase.data)
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			decoded, err := decode(data, "utf16le")
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if decoded != testCase.expectedData {
+				t.Errorf("Got %s, but expected %s", decoded, testCase.expectedData)
+			}
+		})
+	}
+}
+
+func Test_MapAlternatives(t *testing.T) {
+	var doc struct {
+		First  string `test:"a|b"`
+		Second string `test:"c|d"`
+	}
+
+	values := map[string][]byte{"a": {}, "b": []byte("B"), "c": []byte("C"), "d": []byte("D")}
+	err := Map(&doc, "test", testSource(values))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if doc.First != "B" || doc.Second != "C" {
+		t.Errorf("Expected first non-empty values, but got %+v", doc)
+	}
+}
+
+func Test_MapInvalid(t *testing.T) {
+	source := testSource{"a": []byte("1")}
+
+	var unsupported struct {
+		Number int `test:"a"`
+	}
+
+	var unknownEncoding struct {
+		Text string `test:"a" enc:"latin1"`
+	}
+
+	var unknownDate struct {
+		Text string `test:"a" date:"mdy"`
+	}
+
+	var missingDateFormat struct {
+		Date document.Date `test:"a"`
+	}
+
+	testCases := []struct {
+		name string
+		v    any
+	}{
+		{"not pointer", unsupported},
+		{"nil pointer", (*testDocument)(nil)},
+		{"unsupported type", &unsupported},
+		{"unknown encoding", &unknownEncoding},
+		{"unknown date format", &unknownDate},
+		{"missing date format", &missingDateFormat},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := Map(testCase.v, "test", source)
+			if !errors.Is(err, ErrInvalidMapping) {
+				t.Errorf("Expected invalid mapping error, but got %v", err)
+			}
+		})
+	}
+
+	var badKey struct {
+		Text string `test:"bad"`
+	}
+
+	err := Map(&badKey, "test", source)
+	if !errors.Is(err, errBadKey) {
+		t.Errorf("Expected lookup error, but got %v", err)
+	}
+}
+
+func Test_MapExtra(t *testing.T) {
+	type extra map[string][]byte
+
+	var doc struct {
+		Name  string `test:"name"`
+		Extra extra  `test:"*"`
+	}
+
+	err := Map(&doc, "test", testSource{"name": []byte("A"), "other": []byte("B")})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	err = Map(&doc, "test", testSource{"another": []byte("C")})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if doc.Name != "A" || len(doc.Extra) != 2 || string(doc.Extra["other"]) != "B" || string(doc.Extra["another"]) != "C" {
+		t.Errorf("Expected unmapped values from both sources, but got %+v", doc)
+	}
+
+	var invalid struct {
+		Extra map[string]string `test:"*"`
+	}
+
+	err = Map(&invalid, "test", testSource{})
+	if !errors.Is(err, ErrInvalidMapping) {
+		t.Errorf("Expected invalid mapping error, but got %v", err)
+	}
+}
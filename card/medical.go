@@ -1,19 +1,20 @@
 package card
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"strings"
 
 	"github.com/ubavic/bas-celik/card/tlv"
 	"github.com/ubavic/bas-celik/document"
-	"github.com/ubavic/bas-celik/localization"
 	"golang.org/x/text/encoding/unicode"
 	"golang.org/x/text/transform"
 )
 
 // Represents a smart card that holds a Serbian medical insurance document.
 type MedicalCard struct {
+	readTracker
 	atr                  Atr
 	smartCard            Card
 	medicalDocumentFile  []byte
@@ -35,6 +36,9 @@ var MEDICAL_ATR_2 = Atr([]byte{
 	0x31, 0x56, 0x30, 0x0D, 0x0A, 0x6E,
 })
 
+// Application identifier of the medical card application (SERVSZK).
+var MEDICAL_AID = []byte{0xF3, 0x81, 0x00, 0x00, 0x02, 0x53, 0x45, 0x52, 0x56, 0x53, 0x5A, 0x4B, 0x01}
+
 // Location of the file with document data.
 var MED_DOCUMENT_FILE_LOC = []byte{0x0D, 0x01}
 
@@ -48,10 +52,13 @@ var MED_VARIABLE_PERSONAL_FILE_LOC = []byte{0x0D, 0x03}
 var MED_VARIABLE_ADMIN_FILE_LOC = []byte{0x0D, 0x04}
 
 func (card *MedicalCard) InitCard() error {
-	s1 := []byte{0xF3, 0x81, 0x00, 0x00, 0x02, 0x53, 0x45, 0x52, 0x56, 0x53, 0x5A, 0x4B, 0x01}
-	apu := buildAPDU(0x00, 0xA4, 0x04, 0x00, s1, 0)
+	return card.InitCardContext(context.Background())
+}
+
+func (card *MedicalCard) InitCardContext(ctx context.Context) error {
+	apu := buildAPDU(0x00, 0xA4, 0x04, 0x00, MEDICAL_AID, 0)
 
-	rsp, err := card.smartCard.Transmit(apu)
+	rsp, err := transmit(ctx, card.smartCard, apu)
 	if err != nil {
 		return err
 	}
@@ -64,26 +71,41 @@ func (card *MedicalCard) InitCard() error {
 }
 
 func (card *MedicalCard) ReadCard() error {
+	return card.ReadCardContext(context.Background())
+}
+
+func (card *MedicalCard) ReadCardContext(ctx context.Context) error {
+	card.startRead(card.countWanted(PartDocument, PartPersonal, PartVariablePersonal, PartAdministrative))
+	defer card.endRead()
+
 	var err error
 
-	card.medicalDocumentFile, err = card.ReadFile(MED_DOCUMENT_FILE_LOC)
-	if err != nil {
-		return fmt.Errorf("reading document file: %w", err)
+	if card.wants(PartDocument) {
+		card.medicalDocumentFile, err = card.ReadFileContext(ctx, MED_DOCUMENT_FILE_LOC)
+		if err != nil {
+			return fmt.Errorf("reading document file: %w", err)
+		}
 	}
 
-	card.fixedPersonalFile, err = card.ReadFile(MED_FIXED_PERSONAL_FILE_LOC)
-	if err != nil {
-		return fmt.Errorf("reading fixed personal file: %w", err)
+	if card.wants(PartPersonal) {
+		card.fixedPersonalFile, err = card.ReadFileContext(ctx, MED_FIXED_PERSONAL_FILE_LOC)
+		if err != nil {
+			return fmt.Errorf("reading fixed personal file: %w", err)
+		}
 	}
 
-	card.variablePersonalFile, err = card.ReadFile(MED_VARIABLE_PERSONAL_FILE_LOC)
-	if err != nil {
-		return fmt.Errorf("reading variable personal file: %w", err)
+	if card.wants(PartVariablePersonal) {
+		card.variablePersonalFile, err = card.ReadFileContext(ctx, MED_VARIABLE_PERSONAL_FILE_LOC)
+		if err != nil {
+			return fmt.Errorf("reading variable personal file: %w", err)
+		}
 	}
 
-	card.variableAdminFile, err = card.ReadFile(MED_VARIABLE_ADMIN_FILE_LOC)
-	if err != nil {
-		return fmt.Errorf("reading variable administrative file: %w", err)
+	if card.wants(PartAdministrative) {
+		card.variableAdminFile, err = card.ReadFileContext(ctx, MED_VARIABLE_ADMIN_FILE_LOC)
+		if err != nil {
+			return fmt.Errorf("reading variable administrative file: %w", err)
+		}
 	}
 
 	return nil
@@ -92,24 +114,32 @@ func (card *MedicalCard) ReadCard() error {
 func (card *MedicalCard) GetDocument() (document.Document, error) {
 	doc := document.MedicalDocument{}
 
-	err := parseMedicalDocumentFile(card.medicalDocumentFile, &doc)
-	if err != nil {
-		return nil, fmt.Errorf("parsing document file: %w", err)
+	if card.medicalDocumentFile != nil {
+		err := parseMedicalDocumentFile(card.medicalDocumentFile, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing document file: %w", err)
+		}
 	}
 
-	err = parseMedicalFixedPersonalFile(card.fixedPersonalFile, &doc)
-	if err != nil {
-		return nil, fmt.Errorf("parsing fixed personal file: %w", err)
+	if card.fixedPersonalFile != nil {
+		err := parseMedicalFixedPersonalFile(card.fixedPersonalFile, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing fixed personal file: %w", err)
+		}
 	}
 
-	err = parseMedicalVariablePersonalFile(card.variablePersonalFile, &doc)
-	if err != nil {
-		return nil, fmt.Errorf("parsing variable personal file: %w", err)
+	if card.variablePersonalFile != nil {
+		err := parseMedicalVariablePersonalFile(card.variablePersonalFile, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing variable personal file: %w", err)
+		}
 	}
 
-	err = parseMedicalVariableAdminFile(card.variableAdminFile, &doc)
-	if err != nil {
-		return nil, fmt.Errorf("parsing variable administrative file: %w", err)
+	if card.variableAdminFile != nil {
+		err := parseMedicalVariableAdminFile(card.variableAdminFile, &doc)
+		if err != nil {
+			return nil, fmt.Errorf("parsing variable administrative file: %w", err)
+		}
 	}
 
 	return &doc, nil
@@ -119,29 +149,20 @@ func (card *MedicalCard) Atr() Atr {
 	return card.atr
 }
 
-// Decodes UTF16 encoded data on medical cards.
-func descramble(fields map[uint][]byte, tag uint) {
-	bs, ok := fields[tag]
-	if ok {
-		utf8, _, err := transform.Bytes(unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder(), bs)
-		if err == nil {
-			fields[tag] = utf8
-			return
-		}
-	}
-
-	fields[tag] = []byte{}
+func (card *MedicalCard) ReadFile(name []byte) ([]byte, error) {
+	return card.ReadFileContext(context.Background(), name)
 }
 
-func (card *MedicalCard) ReadFile(name []byte) ([]byte, error) {
+func (card *MedicalCard) ReadFileContext(ctx context.Context, name []byte) ([]byte, error) {
+	card.startFile()
 	output := make([]byte, 0)
 
-	_, err := card.selectFile(name)
+	_, err := card.selectFile(ctx, name)
 	if err != nil {
 		return nil, fmt.Errorf("selecting file: %w", err)
 	}
 
-	data, err := read(card.smartCard, 0, 4)
+	data, err := read(ctx, card.smartCard, 0, 4)
 	if err != nil {
 		return nil, fmt.Errorf("reading file header: %w", err)
 	}
@@ -152,8 +173,11 @@ func (card *MedicalCard) ReadFile(name []byte) ([]byte, error) {
 	}
 	length := uint(binary.LittleEndian.Uint16(data[2:]))
 
+	fileLength := length
+	card.reportProgress(0, fileLength)
+
 	for length > 0 {
-		data, err := read(card.smartCard, offset, length)
+		data, err := read(ctx, card.smartCard, offset, length)
 		if err != nil {
 			return nil, fmt.Errorf("reading file: %w", err)
 		}
@@ -163,6 +187,7 @@ func (card *MedicalCard) ReadFile(name []byte) ([]byte, error) {
 		}
 
 		output = append(output, data...)
+		card.reportProgress(uint(len(output)), fileLength)
 
 		offset += uint(len(data))
 		length -= uint(len(data))
@@ -171,9 +196,9 @@ func (card *MedicalCard) ReadFile(name []byte) ([]byte, error) {
 	return output, nil
 }
 
-func (card *MedicalCard) selectFile(name []byte) ([]byte, error) {
+func (card *MedicalCard) selectFile(ctx context.Context, name []byte) ([]byte, error) {
 	apu := buildAPDU(0x00, 0xA4, 0x00, 0x00, name, 0)
-	rsp, err := card.smartCard.Transmit(apu)
+	rsp, err := transmit(ctx, card.smartCard, apu)
 	if err != nil {
 		return nil, fmt.Errorf("selecting file: %w", err)
 	}
@@ -183,8 +208,7 @@ func (card *MedicalCard) selectFile(name []byte) ([]byte, error) {
 
 // Newer medical cards share ATR with the ID cards (GEMALTO_ATR_2)
 func (card *MedicalCard) Test() bool {
-	s1 := []byte{0xF3, 0x81, 0x00, 0x00, 0x02, 0x53, 0x45, 0x52, 0x56, 0x53, 0x5A, 0x4B, 0x01}
-	apu := buildAPDU(0x00, 0xA4, 0x04, 0x00, s1, 0)
+	apu := buildAPDU(0x00, 0xA4, 0x04, 0x00, MEDICAL_AID, 0)
 	_, err := card.smartCard.Transmit(apu)
 	if err != nil {
 		return false
@@ -199,9 +223,13 @@ func (card *MedicalCard) Test() bool {
 	if err != nil {
 		return false
 	}
-	descramble(fields, 1553)
+	// Name of the insurer is UTF-16 encoded.
+	insurer, _, err := transform.Bytes(unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder(), fields[1553])
+	if err != nil {
+		return false
+	}
 
-	return strings.Compare(string(fields[1553]), "Републички фонд за здравствено осигурање") == 0
+	return strings.Compare(string(insurer), "Републички фонд за здравствено осигурање") == 0
 }
 
 func parseMedicalDocumentFile(data []byte, doc *document.MedicalDocument) error {
@@ -210,17 +238,7 @@ func parseMedicalDocumentFile(data []byte, doc *document.MedicalDocument) error
 		return err
 	}
 
-	descramble(fields, 1553)
-	tlv.AssignField(fields, 1553, &doc.InsurerName)
-	tlv.AssignField(fields, 1554, &doc.InsurerID)
-	tlv.AssignField(fields, 1555, &doc.CardId)
-	tlv.AssignField(fields, 1557, &doc.DateOfIssue)
-	localization.FormatDate(&doc.DateOfIssue)
-	tlv.AssignField(fields, 1558, &doc.DateOfExpiry)
-	localization.FormatDate(&doc.DateOfExpiry)
-	tlv.AssignField(fields, 1560, &doc.PrintLanguage)
-
-	return nil
+	return tlv.Unmarshal(fields, doc)
 }
 
 func parseMedicalFixedPersonalFile(data []byte, doc *document.MedicalDocument) error {
@@ -228,19 +246,8 @@ func parseMedicalFixedPersonalFile(data []byte, doc *document.MedicalDocument) e
 	if err != nil {
 		return err
 	}
-	descramble(fields, 1570)
-	tlv.AssignField(fields, 1570, &doc.FamilyName)
-	descramble(fields, 1571)
-	tlv.AssignField(fields, 1571, &doc.FamilyNameLatin)
-	descramble(fields, 1572)
-	tlv.AssignField(fields, 1572, &doc.GivenName)
-	descramble(fields, 1573)
-	tlv.AssignField(fields, 1573, &doc.GivenNameLatin)
-	tlv.AssignField(fields, 1574, &doc.DateOfBirth)
-	localization.FormatDate(&doc.DateOfBirth)
-	tlv.AssignField(fields, 1569, &doc.InsurantNumber)
 
-	return nil
+	return tlv.Unmarshal(fields, doc)
 }
 
 func parseMedicalVariablePersonalFile(data []byte, doc *document.MedicalDocument) error {
@@ -248,11 +255,8 @@ func parseMedicalVariablePersonalFile(data []byte, doc *document.MedicalDocument
 	if err != nil {
 		return err
 	}
-	tlv.AssignField(fields, 1586, &doc.ValidUntil)
-	localization.FormatDate(&doc.ValidUntil)
-	tlv.AssignBoolField(fields, 1587, &doc.PermanentlyValid)
 
-	return nil
+	return tlv.Unmarshal(fields, doc)
 }
 
 func parseMedicalVariableAdminFile(data []byte, doc *document.MedicalDocument) error {
@@ -260,55 +264,13 @@ func parseMedicalVariableAdminFile(data []byte, doc *document.MedicalDocument) e
 	if err != nil {
 		return err
 	}
-	descramble(fields, 1601)
-	tlv.AssignField(fields, 1601, &doc.ParentName)
-	descramble(fields, 1602)
-	tlv.AssignField(fields, 1602, &doc.ParentNameLatin)
+
 	if string(fields[1603]) == "01" {
 		doc.Gender = "Mушко"
 	} else {
 		doc.Gender = "Женско"
 	}
-	tlv.AssignField(fields, 1604, &doc.PersonalNumber)
-	descramble(fields, 1605)
-	tlv.AssignField(fields, 1605, &doc.Street)
-	descramble(fields, 1607)
-	tlv.AssignField(fields, 1607, &doc.Municipality)
-	descramble(fields, 1608)
-	tlv.AssignField(fields, 1608, &doc.Place)
-	descramble(fields, 1610)
-	tlv.AssignField(fields, 1610, &doc.Number)
-	descramble(fields, 1612)
-	tlv.AssignField(fields, 1612, &doc.Apartment)
-	tlv.AssignField(fields, 1614, &doc.InsuranceBasisRZZO)
-	descramble(fields, 1615)
-	tlv.AssignField(fields, 1615, &doc.InsuranceDescription)
-	descramble(fields, 1616)
-	tlv.AssignField(fields, 1616, &doc.CarrierRelationship)
-	tlv.AssignBoolField(fields, 1617, &doc.CarrierFamilyMember)
-	tlv.AssignField(fields, 1618, &doc.CarrierIdNumber)
-	tlv.AssignField(fields, 1619, &doc.CarrierInsurantNumber)
-	descramble(fields, 1620)
-	tlv.AssignField(fields, 1620, &doc.CarrierFamilyName)
-	descramble(fields, 1621)
-	tlv.AssignField(fields, 1621, &doc.CarrierFamilyNameLatin)
-	descramble(fields, 1622)
-	tlv.AssignField(fields, 1622, &doc.CarrierGivenName)
-	descramble(fields, 1623)
-	tlv.AssignField(fields, 1623, &doc.CarrierGivenNameLatin)
-	tlv.AssignField(fields, 1624, &doc.InsuranceStartDate)
-	localization.FormatDate(&doc.InsuranceStartDate)
-	descramble(fields, 1626)
-	tlv.AssignField(fields, 1626, &doc.Country)
-	descramble(fields, 1630)
-	tlv.AssignField(fields, 1630, &doc.TaxpayerName)
-	descramble(fields, 1631)
-	tlv.AssignField(fields, 1631, &doc.TaxpayerResidence)
-	tlv.AssignField(fields, 1632, &doc.TaxpayerIdNumber)
-	if len(doc.TaxpayerIdNumber) == 0 {
-		tlv.AssignField(fields, 1633, &doc.TaxpayerIdNumber)
-	}
-	tlv.AssignField(fields, 1634, &doc.TaxpayerActivityCode)
+	delete(fields, 1603)
 
-	return nil
+	return tlv.Unmarshal(fields, doc)
 }
@@ -0,0 +1,78 @@
+package card
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Options that control reading of the card.
+type ReadOptions struct {
+	Progress ProgressFunc // Can be nil
+	Parts    Part         // Zero value means that all parts are read
+}
+
+// Represents a logical part of the document stored on the card.
+// Parts can be combined with the bitwise or operator.
+// ID cards contain document, personal, residence and portrait parts.
+// Medical cards contain document, personal, variable personal and administrative parts.
+// Vehicle and tachograph cards are always read completely.
+// Skipped parts are left empty in the document. Note that PDF of the ID document can't be created without the portrait.
+type Part uint
+
+const (
+	PartDocument Part = 1 << iota
+	PartPersonal
+	PartResidence
+	PartPortrait
+	PartVariablePersonal
+	PartAdministrative
+)
+
+const AllParts = PartDocument | PartPersonal | PartResidence | PartPortrait | PartVariablePersonal | PartAdministrative
+
+var partNames = map[string]Part{
+	"document":         PartDocument,
+	"personal":         PartPersonal,
+	"residence":        PartResidence,
+	"portrait":         PartPortrait,
+	"variablePersonal": PartVariablePersonal,
+	"administrative":   PartAdministrative,
+}
+
+// Parses a comma separated list of part names (e.g. "document,personal").
+func ParseParts(names string) (Part, error) {
+	parts := Part(0)
+
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		part, ok := partNames[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown part %q", name)
+		}
+
+		parts |= part
+	}
+
+	return parts, nil
+}
+
+// Checks if the part should be read.
+func (tracker *readTracker) wants(part Part) bool {
+	return tracker.options.Parts == 0 || tracker.options.Parts&part != 0
+}
+
+// Returns the number of given parts that should be read.
+func (tracker *readTracker) countWanted(parts ...Part) int {
+	count := 0
+	for _, part := range parts {
+		if tracker.wants(part) {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,34 @@
+package card_test
+
+import (
+	"testing"
+
+	"github.com/ubavic/bas-celik/card"
+)
+
+func Test_ParseParts(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected card.Part
+		err      bool
+	}{
+		{"", 0, false},
+		{"document", card.PartDocument, false},
+		{"document, personal,portrait", card.PartDocument | card.PartPersonal | card.PartPortrait, false},
+		{"variablePersonal,administrative,", card.PartVariablePersonal | card.PartAdministrative, false},
+		{"photo", 0, true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.value, func(t *testing.T) {
+			parts, err := card.ParseParts(testCase.value)
+			if (err != nil) != testCase.err {
+				t.Errorf("Unexpected error %v", err)
+			}
+
+			if parts != testCase.expected {
+				t.Errorf("Expected %b, but got %b", testCase.expected, parts)
+			}
+		})
+	}
+}
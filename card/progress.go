@@ -0,0 +1,77 @@
+package card
+
+// Represents progress of the card read.
+type Progress struct {
+	File       int  // Number of the file that is being read, starting from 1
+	FileCount  int  // Total number of files that will be read
+	BytesRead  uint // Number of bytes read from the current file
+	FileLength uint // Length of the current file, usually read from the file header
+}
+
+// Function that is called each time a chunk of data is read from the card.
+type ProgressFunc func(Progress)
+
+// Keeps the read options and the state of the card read.
+// It is embedded into each card type.
+type readTracker struct {
+	options   ReadOptions
+	file      int
+	fileCount int
+	reading   bool
+}
+
+// Returns completed fraction of the read, between 0 and 1.
+// Each file contributes equally regardless of its length.
+func (progress Progress) Fraction() float64 {
+	if progress.FileCount == 0 || progress.File == 0 {
+		return 0
+	}
+
+	fileFraction := 1.0
+	if progress.FileLength > 0 {
+		fileFraction = min(float64(progress.BytesRead)/float64(progress.FileLength), 1)
+	}
+
+	return (float64(progress.File-1) + fileFraction) / float64(progress.FileCount)
+}
+
+func (tracker *readTracker) SetReadOptions(options ReadOptions) {
+	tracker.options = options
+}
+
+// Starts a read of the given number of files.
+func (tracker *readTracker) startRead(fileCount int) {
+	tracker.file = 0
+	tracker.fileCount = fileCount
+	tracker.reading = true
+}
+
+// Ends the read, so files that are read later (e.g. while the card is tested) are not counted.
+func (tracker *readTracker) endRead() {
+	tracker.reading = false
+}
+
+// Starts a read of the next file. Files are counted only during the read of the card.
+func (tracker *readTracker) startFile() {
+	if !tracker.reading {
+		return
+	}
+
+	tracker.file++
+	if tracker.file > tracker.fileCount {
+		tracker.fileCount = tracker.file
+	}
+}
+
+func (tracker *readTracker) reportProgress(bytesRead, fileLength uint) {
+	if tracker.options.Progress == nil || !tracker.reading {
+		return
+	}
+
+	tracker.options.Progress(Progress{
+		File:       tracker.file,
+		FileCount:  tracker.fileCount,
+		BytesRead:  bytesRead,
+		FileLength: fileLength,
+	})
+}
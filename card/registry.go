@@ -0,0 +1,181 @@
+package card
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Describes a type of the smart card document that can be detected and read.
+// Card types are registered with RegisterCardType.
+type CardType struct {
+	Type   CardDocumentType                    // Assigned by RegisterCardType if zero
+	Name   string                              // Name used in the ATR table, e.g. "medical"
+	Atrs   []string                            // ATR patterns in the ATR table format. Can be empty if the ATR table already lists the card
+	Probed bool                                // Card type is probed with Test when the ATR is not found in the ATR table
+	New    func(atr Atr, sc Card) CardDocument // Creates the card document (a pointer) that communicates with sc
+}
+
+var ErrInvalidCardType = errors.New("invalid card type")
+
+// Guards the registered card types and the ATR table.
+var registryMu sync.RWMutex
+
+// Registered card types, in the order in which unknown cards are probed.
+// Apollo cards are not probed since they can't be recognized by reading card content.
+var cardTypes = []CardType{
+	{
+		Type: ApolloIdDocumentCardType,
+		Name: "apollo",
+		New: func(atr Atr, sc Card) CardDocument {
+			return &Apollo{atr: atr, smartCard: sc}
+		},
+	},
+	{
+		Type:   GemaltoIdDocumentCardType,
+		Name:   "gemalto",
+		Probed: true,
+		New: func(atr Atr, sc Card) CardDocument {
+			return &Gemalto{atr: atr, smartCard: sc}
+		},
+	},
+	{
+		Type:   MedicalDocumentCardType,
+		Name:   "medical",
+		Probed: true,
+		New: func(atr Atr, sc Card) CardDocument {
+			return &MedicalCard{atr: atr, smartCard: sc}
+		},
+	},
+	{
+		Type:   VehicleDocumentCardType,
+		Name:   "vehicle",
+		Probed: true,
+		New: func(atr Atr, sc Card) CardDocument {
+			return &VehicleCard{atr: atr, smartCard: sc}
+		},
+	},
+	{
+		Type:   TachographDocumentCardType,
+		Name:   "tachograph",
+		Probed: true,
+		New: func(atr Atr, sc Card) CardDocument {
+			return &TachographCard{atr: atr, smartCard: sc}
+		},
+	},
+}
+
+// Registers a new card type, so it can be detected by DetectCardDocument.
+// If the Type field is zero, a new CardDocumentType is assigned. The registered type is returned.
+// ATR patterns are added after the existing entries of the ATR table,
+// so they don't change detection of the already known cards.
+// Card types should be registered before the cards are detected, usually in the init function.
+func RegisterCardType(cardType CardType) (CardDocumentType, error) {
+	if len(cardType.Name) == 0 || cardType.New == nil {
+		return 0, fmt.Errorf("%w: name and constructor are required", ErrInvalidCardType)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	maxType := UnknownDocumentCardType
+	for _, registered := range cardTypes {
+		if strings.EqualFold(registered.Name, cardType.Name) {
+			return 0, fmt.Errorf("%w: card %q is already registered", ErrInvalidCardType, cardType.Name)
+		}
+
+		if cardType.Type != UnknownDocumentCardType && registered.Type == cardType.Type {
+			return 0, fmt.Errorf("%w: type %d is already registered", ErrInvalidCardType, cardType.Type)
+		}
+
+		maxType = max(maxType, registered.Type)
+	}
+
+	if cardType.Type == UnknownDocumentCardType {
+		cardType.Type = maxType + 1
+	}
+
+	entries := make([]AtrTableEntry, 0, len(cardType.Atrs))
+	for _, atr := range cardType.Atrs {
+		entry := AtrTableEntry{Atr: atr, Cards: []string{cardType.Name}}
+		err := entry.compilePattern()
+		if err != nil {
+			return 0, err
+		}
+
+		entry.cardTypes = []CardDocumentType{cardType.Type}
+		entries = append(entries, entry)
+	}
+
+	cardTypes = append(cardTypes, cardType)
+	atrTable = append(atrTable, entries...)
+
+	return cardType.Type, nil
+}
+
+// Returns the registered card type with the given name. Names are case insensitive.
+func lookupCardTypeByName(name string) (CardType, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, cardType := range cardTypes {
+		if strings.EqualFold(cardType.Name, name) {
+			return cardType, true
+		}
+	}
+
+	return CardType{}, false
+}
+
+// Returns the registered card type.
+func lookupCardType(documentType CardDocumentType) (CardType, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, cardType := range cardTypes {
+		if cardType.Type == documentType {
+			return cardType, true
+		}
+	}
+
+	return CardType{}, false
+}
+
+// Card types of the documents created by the latest detection, keyed by the documents.
+// Documents of the earlier detections are not kept, so they are released together with their cards.
+var detectedCardTypes = map[CardDocument]CardDocumentType{}
+
+var detectedMu sync.Mutex
+
+// Replaces the card types of the detected documents.
+func setDetectedCardTypes(detected map[CardDocument]CardDocumentType) {
+	detectedMu.Lock()
+	defer detectedMu.Unlock()
+
+	detectedCardTypes = detected
+}
+
+// Returns the type of the card document, if the document was created by the latest detection.
+func detectedCardType(cardDoc CardDocument) (CardDocumentType, bool) {
+	detectedMu.Lock()
+	defer detectedMu.Unlock()
+
+	documentType, ok := detectedCardTypes[cardDoc]
+	return documentType, ok
+}
+
+// Returns card types that are probed when the ATR is not found in the ATR table.
+func probedCardTypes() []CardDocumentType {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	probed := []CardDocumentType{}
+	for _, cardType := range cardTypes {
+		if cardType.Probed {
+			probed = append(probed, cardType.Type)
+		}
+	}
+
+	return probed
+}
@@ -0,0 +1,88 @@
+package card_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ebfe/scard"
+	"github.com/ubavic/bas-celik/card"
+	"github.com/ubavic/bas-celik/document"
+)
+
+// Card document of a card type that is not built into the card package.
+type badgeCard struct {
+	atr card.Atr
+}
+
+func (c *badgeCard) ReadFile(_ []byte) ([]byte, error) { return nil, nil }
+func (c *badgeCard) ReadFileContext(_ context.Context, _ []byte) ([]byte, error) {
+	return nil, nil
+}
+func (c *badgeCard) InitCard() error                         { return nil }
+func (c *badgeCard) InitCardContext(_ context.Context) error { return nil }
+func (c *badgeCard) ReadCard() error                         { return nil }
+func (c *badgeCard) ReadCardContext(_ context.Context) error { return nil }
+func (c *badgeCard) SetReadOptions(_ card.ReadOptions)       {}
+func (c *badgeCard) GetDocument() (document.Document, error) { return nil, nil }
+func (c *badgeCard) Test() bool                              { return true }
+func (c *badgeCard) Atr() card.Atr                           { return c.atr }
+
+// Virtual card with the given ATR that doesn't respond to any command.
+type atrCard struct {
+	atr card.Atr
+}
+
+func (c *atrCard) Status() (*scard.CardStatus, error) {
+	return &scard.CardStatus{Atr: c.atr}, nil
+}
+
+func (c *atrCard) Transmit(_ []byte) ([]byte, error) {
+	return []byte{0x6A, 0x82}, nil
+}
+
+func Test_RegisterCardType(t *testing.T) {
+	badgeType, err := card.RegisterCardType(card.CardType{
+		Name: "badge",
+		Atrs: []string{"3b 03 14 .. .."},
+		New: func(atr card.Atr, _ card.Card) card.CardDocument {
+			return &badgeCard{atr: atr}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if badgeType <= card.TachographDocumentCardType {
+		t.Errorf("Expected new card document type, but got %d", badgeType)
+	}
+
+	cardDoc, err := card.DetectCardDocument(&atrCard{atr: card.Atr{0x3B, 0x03, 0x14, 0x50, 0x51}})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if _, ok := cardDoc.(*badgeCard); !ok {
+		t.Errorf("Expected badge card, but got %T", cardDoc)
+	}
+
+	_, err = card.ParseAtrTable([]byte(`[{"atr": "3b0315ffff", "cards": ["badge"]}]`))
+	if err != nil {
+		t.Errorf("Expected registered card in the ATR table, but got error %v", err)
+	}
+
+	_, err = card.RegisterCardType(card.CardType{
+		Name: "Badge",
+		New: func(atr card.Atr, _ card.Card) card.CardDocument {
+			return &badgeCard{atr: atr}
+		},
+	})
+	if !errors.Is(err, card.ErrInvalidCardType) {
+		t.Errorf("Expected error for duplicate card type, but got %v", err)
+	}
+
+	_, err = card.RegisterCardType(card.CardType{Name: "empty"})
+	if !errors.Is(err, card.ErrInvalidCardType) {
+		t.Errorf("Expected error for card type without constructor, but got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package card
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Represents a diagnostic report about a card that couldn't be recognized.
+// The report contains only the ATR, the reader name, and statuses of SELECT commands
+// for known applications. Data returned by the card is never included, only its length.
+type Report struct {
+	Version  string
+	Reader   string
+	Atr      string
+	AtrInfo  *AtrInfo `json:",omitempty"`
+	AtrError string   `json:",omitempty"`
+	Probes   []ReportProbe
+}
+
+// Represents a response of the card to the SELECT command for a single application.
+type ReportProbe struct {
+	Card           string
+	Aid            string
+	Status         string `json:",omitempty"`
+	ResponseLength int
+	Error          string `json:",omitempty"`
+}
+
+// Application that is selected while creating the report.
+// P2 parameter of the SELECT command is the same as the one used by the card type.
+type reportAid struct {
+	card string
+	aid  []byte
+	p2   byte
+}
+
+// Reader names sometimes contain serial numbers of the reader in parentheses.
+var readerSerialRegexp = regexp.MustCompile(`\s*\([^)]*\)`)
+
+// Creates a report by sending SELECT commands for each known application to the card.
+func CreateReport(sc Card, reader, version string) (*Report, error) {
+	smartCardStatus, err := sc.Status()
+	if err != nil {
+		return nil, fmt.Errorf("reading card status %w", err)
+	}
+
+	atr := Atr(smartCardStatus.Atr)
+
+	report := Report{
+		Version: strings.TrimSpace(version),
+		Reader:  readerSerialRegexp.ReplaceAllString(reader, ""),
+		Atr:     atr.String(),
+		Probes:  []ReportProbe{},
+	}
+
+	report.AtrInfo, err = ParseAtr(atr)
+	if err != nil {
+		report.AtrError = err.Error()
+	}
+
+	aids := []reportAid{
+		{"gemalto", GEMALTO_ID_AID, 0x00},
+		{"gemalto", GEMALTO_IF_AID, 0x00},
+		{"gemalto", GEMALTO_RP_AID, 0x00},
+		{"medical", MEDICAL_AID, 0x00},
+	}
+
+	// The last application of each vehicle sequence is selected without the response data.
+	for _, sequence := range VEHICLE_AIDS {
+		for i, aid := range sequence {
+			p2 := byte(0x00)
+			if i == len(sequence)-1 {
+				p2 = 0x0C
+			}
+			aids = append(aids, reportAid{"vehicle", aid, p2})
+		}
+	}
+
+	aids = append(aids, reportAid{"tachograph", TACHOGRAPH_AID, 0x0C})
+
+	for _, aid := range aids {
+		probe := ReportProbe{
+			Card: aid.card,
+			Aid:  hex.EncodeToString(aid.aid),
+		}
+
+		apu := buildAPDU(0x00, 0xA4, 0x04, aid.p2, aid.aid, 0)
+		rsp, err := sc.Transmit(apu)
+		if err != nil {
+			probe.Error = err.Error()
+		} else if len(rsp) < 2 {
+			probe.Error = "bad status code"
+		} else {
+			probe.Status = hex.EncodeToString(rsp[len(rsp)-2:])
+			probe.ResponseLength = len(rsp) - 2
+		}
+
+		report.Probes = append(report.Probes, probe)
+	}
+
+	return &report, nil
+}
+
+// Returns report in the plain text format, suitable for pasting into an issue.
+func (report *Report) Text() string {
+	lines := []string{
+		"Version: " + report.Version,
+		"Reader: " + report.Reader,
+		"",
+	}
+
+	if report.AtrInfo != nil {
+		lines = append(lines, report.AtrInfo.String())
+	} else {
+		lines = append(lines, "ATR: "+report.Atr, "ATR error: "+report.AtrError)
+	}
+
+	lines = append(lines, "", "Probes:")
+	for _, probe := range report.Probes {
+		result := fmt.Sprintf("SW=%s, %d bytes", probe.Status, probe.ResponseLength)
+		if probe.Error != "" {
+			result = "error: " + probe.Error
+		}
+
+		lines = append(lines, fmt.Sprintf("%-10s %s: %s", probe.Card, probe.Aid, result))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (report *Report) Json() ([]byte, error) {
+	return json.MarshalIndent(report, "", "  ")
+}
@@ -0,0 +1,92 @@
+package card_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ebfe/scard"
+	"github.com/ubavic/bas-celik/card"
+)
+
+// Virtual card that responds only to the SELECT command of the medical application.
+// P2 parameters of the SELECT commands are recorded by the application.
+type reportTestCard struct {
+	p2 map[string]byte
+}
+
+func (c *reportTestCard) Status() (*scard.CardStatus, error) {
+	return &scard.CardStatus{Atr: card.MEDICAL_ATR_1}, nil
+}
+
+func (c *reportTestCard) Transmit(apdu []byte) ([]byte, error) {
+	c.p2[hex.EncodeToString(apdu[5:5+apdu[4]])] = apdu[3]
+
+	if bytes.Contains(apdu, card.MEDICAL_AID) {
+		return []byte{0x6F, 0x01, 0x00, 0x90, 0x00}, nil
+	}
+
+	if bytes.Contains(apdu, card.TACHOGRAPH_AID) {
+		return nil, errors.New("transmit failed")
+	}
+
+	return []byte{0x6A, 0x82}, nil
+}
+
+func Test_CreateReport(t *testing.T) {
+	testCard := &reportTestCard{p2: map[string]byte{}}
+	report, err := card.CreateReport(testCard, "ACS ACR39U ICC Reader (123456789) 00 00", "1.0.0\n")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if report.Reader != "ACS ACR39U ICC Reader 00 00" {
+		t.Errorf("Expected reader serial number to be removed, but got %s", report.Reader)
+	}
+
+	if report.Version != "1.0.0" || report.Atr != card.MEDICAL_ATR_1.String() || report.AtrInfo == nil {
+		t.Errorf("Unexpected report header %+v", report)
+	}
+
+	if len(report.Probes) != 14 {
+		t.Fatalf("Expected 14 probes, but got %d", len(report.Probes))
+	}
+
+	for _, probe := range report.Probes {
+		switch probe.Card {
+		case "medical":
+			if probe.Status != "9000" || probe.ResponseLength != 3 {
+				t.Errorf("Unexpected medical probe %+v", probe)
+			}
+		case "tachograph":
+			if probe.Error == "" {
+				t.Errorf("Expected error in tachograph probe")
+			}
+		default:
+			if probe.Status != "6a82" || probe.ResponseLength != 0 {
+				t.Errorf("Unexpected probe %+v", probe)
+			}
+		}
+	}
+
+	if testCard.p2[hex.EncodeToString(card.TACHOGRAPH_AID)] != 0x0C || testCard.p2[hex.EncodeToString(card.MEDICAL_AID)] != 0x00 {
+		t.Errorf("Expected SELECT parameters of the card types, but got %v", testCard.p2)
+	}
+
+	text := report.Text()
+	if strings.Contains(text, "6f0100") {
+		t.Errorf("Report should not contain card response")
+	}
+
+	data, err := report.Json()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if !json.Valid(data) {
+		t.Errorf("Expected valid JSON")
+	}
+}
@@ -0,0 +1,277 @@
+package card
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ebfe/scard"
+)
+
+// Default number of times a session recovers from transient errors.
+const DefaultRetries = 3
+
+// Delay between the transient error and the reconnection attempt.
+var retryDelay = 500 * time.Millisecond
+
+// Represents a card that can be reconnected and that supports transactions, such as scard.Card.
+type ReconnectableCard interface {
+	Card
+	Reconnect(scard.ShareMode, scard.Protocol, scard.Disposition) error
+	BeginTransaction() error
+	EndTransaction(scard.Disposition) error
+}
+
+// Represents a connection to the card that survives card resets.
+// When another process resets the card (or it is briefly removed), the session reconnects,
+// re-initializes the card and selects the last selected file again.
+// After that, the failed command is sent again, so the read continues where it stopped.
+// Only commands that select or read files are sent again. Other commands (such as PIN verification)
+// depend on the state of the card that is lost with the reset, so their errors are returned.
+// Session satisfies the Card interface, so it can be used in place of scard.Card.
+type Session struct {
+	mu            sync.Mutex
+	card          ReconnectableCard
+	shareMode     scard.ShareMode
+	maxRetries    int
+	retries       int
+	init          func() error
+	lastSelect    []byte
+	recovering    bool
+	inTransaction bool
+	OnRetry       func(attempt int, err error) // Called before each reconnection attempt. Can be nil
+}
+
+// Creates a session that recovers from at most maxRetries transient errors of a single command.
+// The share mode should be the same as the one used for connecting the card, since it is used for reconnections.
+func NewSession(card ReconnectableCard, shareMode scard.ShareMode, maxRetries int) *Session {
+	return &Session{
+		card:       card,
+		shareMode:  shareMode,
+		maxRetries: maxRetries,
+	}
+}
+
+// Calls f inside a PC/SC transaction, so other applications can't send commands to the card
+// until f returns. If the card is reset during the transaction, the transaction is started again after reconnection.
+// Transient errors while the transaction is started are recovered from in the same way as for commands.
+func (session *Session) Transaction(f func() error) error {
+	for {
+		err := session.card.BeginTransaction()
+		if err == nil {
+			session.resetRetries()
+			break
+		}
+
+		if !session.shouldRetry(err) {
+			return fmt.Errorf("beginning transaction: %w", err)
+		}
+
+		err = session.recover(err, nil)
+		if err != nil {
+			return fmt.Errorf("beginning transaction: %w", err)
+		}
+	}
+
+	session.mu.Lock()
+	session.inTransaction = true
+	session.mu.Unlock()
+
+	err := f()
+
+	session.mu.Lock()
+	session.inTransaction = false
+	session.mu.Unlock()
+
+	endErr := session.card.EndTransaction(scard.LeaveCard)
+	if err != nil {
+		return err
+	}
+
+	if endErr != nil {
+		return fmt.Errorf("ending transaction: %w", endErr)
+	}
+
+	return nil
+}
+
+// Sets the function that initializes the card after a reconnection.
+// Usually this is the InitCard method of the detected CardDocument.
+func (session *Session) SetInit(init func() error) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	session.init = init
+}
+
+func (session *Session) Status() (*scard.CardStatus, error) {
+	for {
+		status, err := session.card.Status()
+		if err == nil {
+			session.resetRetries()
+			return status, nil
+		}
+
+		if !session.shouldRetry(err) {
+			return status, err
+		}
+
+		err = session.recover(err, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (session *Session) Transmit(apdu []byte) ([]byte, error) {
+	if isFileSelect(apdu) {
+		session.mu.Lock()
+		if !session.recovering {
+			session.lastSelect = slices.Clone(apdu)
+		}
+		session.mu.Unlock()
+	}
+
+	for {
+		rsp, err := session.card.Transmit(apdu)
+		if err == nil {
+			session.resetRetries()
+			return rsp, nil
+		}
+
+		if !isResendable(apdu) || !session.shouldRetry(err) {
+			return rsp, err
+		}
+
+		err = session.recover(err, apdu)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Checks if the error is transient and if there are any retries left.
+// Errors during the recovery are not retried, since the recovery itself is retried.
+func (session *Session) shouldRetry(err error) bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	return isTransientError(err) && !session.recovering && session.retries < session.maxRetries
+}
+
+// Resets the number of retries after a successful command, so that retries are counted per command.
+// Commands sent during the recovery don't reset the number, since the recovery is retried as a whole.
+func (session *Session) resetRetries() {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	if !session.recovering {
+		session.retries = 0
+	}
+}
+
+// Reconnects to the card, re-initializes it and selects the last selected file.
+// The file is not selected when the failed command is the file selection itself.
+func (session *Session) recover(cause error, failedApdu []byte) error {
+	session.mu.Lock()
+	session.retries++
+	attempt := session.retries
+	session.recovering = true
+	init := session.init
+	lastSelect := session.lastSelect
+	inTransaction := session.inTransaction
+	onRetry := session.OnRetry
+	session.mu.Unlock()
+
+	defer func() {
+		session.mu.Lock()
+		session.recovering = false
+		session.mu.Unlock()
+	}()
+
+	if onRetry != nil {
+		onRetry(attempt, cause)
+	}
+
+	time.Sleep(retryDelay)
+
+	for {
+		err := session.reconnect(init, lastSelect, failedApdu, inTransaction)
+		if err == nil {
+			return nil
+		}
+
+		session.mu.Lock()
+		retry := isTransientError(err) && session.retries < session.maxRetries
+		if retry {
+			session.retries++
+			attempt = session.retries
+		}
+		session.mu.Unlock()
+
+		if !retry {
+			return fmt.Errorf("recovering from %w: %w", cause, err)
+		}
+
+		if onRetry != nil {
+			onRetry(attempt, err)
+		}
+
+		time.Sleep(retryDelay)
+	}
+}
+
+func (session *Session) reconnect(init func() error, lastSelect, failedApdu []byte, inTransaction bool) error {
+	err := session.card.Reconnect(session.shareMode, scard.ProtocolAny, scard.LeaveCard)
+	if err != nil {
+		return fmt.Errorf("reconnecting: %w", err)
+	}
+
+	if inTransaction {
+		err = session.card.BeginTransaction()
+		if err != nil {
+			return fmt.Errorf("beginning transaction: %w", err)
+		}
+	}
+
+	if init != nil {
+		err = init()
+		if err != nil {
+			return fmt.Errorf("initializing card: %w", err)
+		}
+	}
+
+	if lastSelect != nil && !slices.Equal(lastSelect, failedApdu) {
+		rsp, err := session.card.Transmit(lastSelect)
+		if err != nil {
+			return fmt.Errorf("selecting file: %w", err)
+		}
+
+		if !responseOK(rsp) {
+			return fmt.Errorf("selecting file: response not OK")
+		}
+	}
+
+	return nil
+}
+
+// Checks if the error is caused by a card reset or removal.
+func isTransientError(err error) bool {
+	return errors.Is(err, scard.ErrResetCard) ||
+		errors.Is(err, scard.ErrRemovedCard) ||
+		errors.Is(err, scard.ErrUnpoweredCard) ||
+		errors.Is(err, scard.ErrUnresponsiveCard)
+}
+
+// Checks if the command can be sent again after the reset: SELECT and READ BINARY don't change the card.
+func isResendable(apdu []byte) bool {
+	return len(apdu) > 1 && (apdu[1] == 0xA4 || apdu[1] == 0xB0)
+}
+
+// Checks if the command selects a file (but not an application).
+// Applications are selected by the card initialization.
+func isFileSelect(apdu []byte) bool {
+	return len(apdu) > 2 && apdu[1] == 0xA4 && apdu[2] != 0x04
+}
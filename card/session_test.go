@@ -0,0 +1,217 @@
+package card
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/ebfe/scard"
+)
+
+// Virtual card that is reset before the listed transmissions (counting from 1).
+type resetCard struct {
+	medicalFileCard
+	resetAt    []int
+	transmits  int
+	reconnects int
+	commands   [][]byte
+	// Number of started transactions that are not ended
+	transactions int
+	// Number of attempts to start the transaction that fail because of the reset
+	resetsAtBegin int
+}
+
+func (card *resetCard) Transmit(apdu []byte) ([]byte, error) {
+	card.transmits++
+	if slices.Contains(card.resetAt, card.transmits) {
+		return nil, scard.ErrResetCard
+	}
+
+	card.commands = append(card.commands, apdu)
+	return card.medicalFileCard.Transmit(apdu)
+}
+
+func (card *resetCard) Reconnect(_ scard.ShareMode, _ scard.Protocol, _ scard.Disposition) error {
+	card.reconnects++
+	card.transactions = 0
+	return nil
+}
+
+func (card *resetCard) BeginTransaction() error {
+	if card.resetsAtBegin > 0 {
+		card.resetsAtBegin--
+		return scard.ErrResetCard
+	}
+
+	card.transactions++
+	return nil
+}
+
+func (card *resetCard) EndTransaction(_ scard.Disposition) error {
+	card.transactions--
+	return nil
+}
+
+func Test_SessionRecovers(t *testing.T) {
+	retryDelay = 0
+
+	// Transmissions: select file, read header, read 10 bytes, read 10 bytes...
+	virtualCard := &resetCard{resetAt: []int{4}}
+	session := NewSession(virtualCard, scard.ShareShared, DefaultRetries)
+
+	card := MedicalCard{smartCard: session}
+	session.SetInit(card.InitCard)
+
+	retries := 0
+	session.OnRetry = func(_ int, err error) {
+		retries++
+		if !errors.Is(err, scard.ErrResetCard) {
+			t.Errorf("Expected reset error, but got %v", err)
+		}
+	}
+
+	data, err := card.ReadFile(MED_DOCUMENT_FILE_LOC)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if len(data) != 24 {
+		t.Errorf("Expected 24 bytes, but got %d", len(data))
+	}
+
+	if virtualCard.reconnects != 1 || retries != 1 {
+		t.Errorf("Expected single retry, but got %d reconnects and %d retries", virtualCard.reconnects, retries)
+	}
+
+	// After the reset, the application and the file are selected, and the read is repeated from the same offset.
+	expected := [][]byte{
+		buildAPDU(0x00, 0xA4, 0x04, 0x00, MEDICAL_AID, 0),
+		buildAPDU(0x00, 0xA4, 0x00, 0x00, MED_DOCUMENT_FILE_LOC, 0),
+		buildAPDU(0x00, 0xB0, 0x00, 0x0E, nil, 0x0E),
+	}
+
+	for i, command := range expected {
+		if !slices.Equal(virtualCard.commands[3+i], command) {
+			t.Errorf("Expected command %X, but got %X", command, virtualCard.commands[3+i])
+		}
+	}
+}
+
+func Test_SessionRetriesExhausted(t *testing.T) {
+	retryDelay = 0
+
+	virtualCard := &resetCard{resetAt: []int{2, 3, 4, 5}}
+	session := NewSession(virtualCard, scard.ShareShared, 2)
+
+	card := MedicalCard{smartCard: session}
+
+	_, err := card.ReadFile(MED_DOCUMENT_FILE_LOC)
+	if !errors.Is(err, scard.ErrResetCard) {
+		t.Errorf("Expected reset error, but got %v", err)
+	}
+
+	if virtualCard.reconnects != 2 {
+		t.Errorf("Expected 2 reconnects, but got %d", virtualCard.reconnects)
+	}
+}
+
+func Test_SessionRetriesPerCommand(t *testing.T) {
+	retryDelay = 0
+
+	// Each read is interrupted by a single reset, which is more than the limit for the whole file.
+	virtualCard := &resetCard{resetAt: []int{2, 5, 8}}
+	session := NewSession(virtualCard, scard.ShareShared, 1)
+
+	card := MedicalCard{smartCard: session}
+
+	_, err := card.ReadFile(MED_DOCUMENT_FILE_LOC)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if virtualCard.reconnects != 3 {
+		t.Errorf("Expected 3 reconnects, but got %d", virtualCard.reconnects)
+	}
+}
+
+func Test_SessionDoesNotResendVerify(t *testing.T) {
+	retryDelay = 0
+
+	virtualCard := &resetCard{resetAt: []int{1}}
+	session := NewSession(virtualCard, scard.ShareShared, DefaultRetries)
+
+	_, err := session.Transmit(buildAPDU(0x00, 0x20, 0x00, 0x80, PadPin("1234"), 0))
+	if !errors.Is(err, scard.ErrResetCard) {
+		t.Errorf("Expected reset error, but got %v", err)
+	}
+
+	if virtualCard.reconnects != 0 || len(virtualCard.commands) != 0 {
+		t.Errorf("Expected command not to be sent again, but got %d reconnects and commands %X", virtualCard.reconnects, virtualCard.commands)
+	}
+}
+
+func Test_SessionTransaction(t *testing.T) {
+	retryDelay = 0
+
+	virtualCard := &resetCard{resetAt: []int{4}}
+	session := NewSession(virtualCard, scard.ShareExclusive, DefaultRetries)
+
+	card := MedicalCard{smartCard: session}
+
+	err := session.Transaction(func() error {
+		if virtualCard.transactions != 1 {
+			t.Errorf("Expected transaction to be started")
+		}
+
+		_, err := card.ReadFile(MED_DOCUMENT_FILE_LOC)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	// The reset ends the transaction, so it has to be started again before it is ended.
+	if virtualCard.transactions != 0 || virtualCard.reconnects != 1 {
+		t.Errorf("Expected transaction to be restarted after the reset and ended, but got %d", virtualCard.transactions)
+	}
+
+	err = session.Transaction(func() error {
+		return errFailedRead
+	})
+	if !errors.Is(err, errFailedRead) {
+		t.Errorf("Expected read error, but got %v", err)
+	}
+}
+
+func Test_SessionTransactionBeginRecovers(t *testing.T) {
+	retryDelay = 0
+
+	virtualCard := &resetCard{resetsAtBegin: 1}
+	session := NewSession(virtualCard, scard.ShareExclusive, DefaultRetries)
+
+	called := false
+	err := session.Transaction(func() error {
+		called = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if !called || virtualCard.reconnects != 1 || virtualCard.transactions != 0 {
+		t.Errorf("Expected transaction to be started after the reconnection, but got %d reconnects", virtualCard.reconnects)
+	}
+
+	virtualCard = &resetCard{resetsAtBegin: 5}
+	session = NewSession(virtualCard, scard.ShareExclusive, 2)
+
+	err = session.Transaction(func() error {
+		t.Errorf("Unexpected call")
+		return nil
+	})
+	if !errors.Is(err, scard.ErrResetCard) {
+		t.Errorf("Expected reset error, but got %v", err)
+	}
+}
+
+var errFailedRead = errors.New("failed read")
@@ -0,0 +1,454 @@
+package card
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ubavic/bas-celik/card/cardErrors"
+	"github.com/ubavic/bas-celik/document"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Represents a smart card that holds an EU digital tachograph driver card application.
+// Structure of the card is described in the Annex 1B of the Commission Regulation (EEC) No 3821/85.
+type TachographCard struct {
+	readTracker
+	atr                     Atr
+	smartCard               Card
+	activityStructureLength uint
+	identificationFile      []byte
+	licenceFile             []byte
+	activityFile            []byte
+}
+
+// Identifier of the tachograph application.
+var TACHOGRAPH_AID = []byte{0xFF, 0x54, 0x41, 0x43, 0x48, 0x4F}
+
+// Location of the file with application identification.
+var TACHO_APPLICATION_FILE_LOC = []byte{0x05, 0x01}
+
+// Location of the file with card and card holder identification.
+var TACHO_IDENTIFICATION_FILE_LOC = []byte{0x05, 0x20}
+
+// Location of the file with driving licence information.
+var TACHO_LICENCE_FILE_LOC = []byte{0x05, 0x21}
+
+// Location of the file with driver activity data.
+var TACHO_ACTIVITY_FILE_LOC = []byte{0x05, 0x04}
+
+const tachoApplicationFileSize = 10
+const tachoIdentificationFileSize = 143
+const tachoLicenceFileSize = 53
+
+// Type of the tachograph card stored in the application identification file.
+const tachoDriverCardType = 0x01
+
+func (card *TachographCard) InitCard() error {
+	return card.InitCardContext(context.Background())
+}
+
+func (card *TachographCard) InitCardContext(ctx context.Context) error {
+	apu := buildAPDU(0x00, 0xA4, 0x04, 0x0C, TACHOGRAPH_AID, 0)
+	rsp, err := transmit(ctx, card.smartCard, apu)
+	if err != nil {
+		return fmt.Errorf("selecting tachograph application: %w", err)
+	}
+
+	if !responseOK(rsp) {
+		return errors.New("selecting tachograph application: response not OK")
+	}
+
+	data, err := card.readFixedFile(ctx, TACHO_APPLICATION_FILE_LOC, tachoApplicationFileSize)
+	if err != nil {
+		return fmt.Errorf("reading application file: %w", err)
+	}
+
+	if len(data) < tachoApplicationFileSize {
+		return cardErrors.ErrInvalidLength
+	}
+
+	if data[0] != tachoDriverCardType {
+		return fmt.Errorf("initializing tachograph card: not a driver card (type %d)", data[0])
+	}
+
+	card.activityStructureLength = uint(binary.BigEndian.Uint16(data[5:]))
+
+	return nil
+}
+
+func (card *TachographCard) ReadCard() error {
+	return card.ReadCardContext(context.Background())
+}
+
+func (card *TachographCard) ReadCardContext(ctx context.Context) error {
+	card.startRead(3)
+	defer card.endRead()
+
+	var err error
+
+	card.identificationFile, err = card.ReadFileContext(ctx, TACHO_IDENTIFICATION_FILE_LOC)
+	if err != nil {
+		return fmt.Errorf("reading identification file: %w", err)
+	}
+
+	card.licenceFile, err = card.ReadFileContext(ctx, TACHO_LICENCE_FILE_LOC)
+	if err != nil {
+		return fmt.Errorf("reading driving licence file: %w", err)
+	}
+
+	card.activityFile, err = card.ReadFileContext(ctx, TACHO_ACTIVITY_FILE_LOC)
+	if err != nil {
+		return fmt.Errorf("reading activity file: %w", err)
+	}
+
+	return nil
+}
+
+func (card *TachographCard) GetDocument() (document.Document, error) {
+	doc := document.TachographDocument{}
+
+	err := parseTachographIdentificationFile(card.identificationFile, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("parsing identification file: %w", err)
+	}
+
+	err = parseTachographLicenceFile(card.licenceFile, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("parsing driving licence file: %w", err)
+	}
+
+	err = parseTachographActivityFile(card.activityFile, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("parsing activity file: %w", err)
+	}
+
+	return &doc, nil
+}
+
+func (card *TachographCard) Atr() Atr {
+	return card.atr
+}
+
+// Reads one of the elementary files of the tachograph application.
+// Tachograph files don't have a header, so the size of each file is determined from the specification.
+func (card *TachographCard) ReadFile(name []byte) ([]byte, error) {
+	return card.ReadFileContext(context.Background(), name)
+}
+
+func (card *TachographCard) ReadFileContext(ctx context.Context, name []byte) ([]byte, error) {
+	var length uint
+
+	switch {
+	case slices.Equal(name, TACHO_APPLICATION_FILE_LOC):
+		length = tachoApplicationFileSize
+	case slices.Equal(name, TACHO_IDENTIFICATION_FILE_LOC):
+		length = tachoIdentificationFileSize
+	case slices.Equal(name, TACHO_LICENCE_FILE_LOC):
+		length = tachoLicenceFileSize
+	case slices.Equal(name, TACHO_ACTIVITY_FILE_LOC):
+		if card.activityStructureLength == 0 {
+			return nil, errors.New("activity structure length unknown")
+		}
+		length = 4 + card.activityStructureLength
+	default:
+		return nil, errors.New("unknown file")
+	}
+
+	return card.readFixedFile(ctx, name, length)
+}
+
+func (card *TachographCard) readFixedFile(ctx context.Context, name []byte, length uint) ([]byte, error) {
+	card.startFile()
+	output := make([]byte, 0, length)
+
+	rsp, err := card.selectFile(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("selecting file: %w", err)
+	}
+
+	if !responseOK(rsp) {
+		return nil, errors.New("selecting file: response not OK")
+	}
+
+	fileLength := length
+	card.reportProgress(0, fileLength)
+
+	offset := uint(0)
+	for length > 0 {
+		data, err := read(ctx, card.smartCard, offset, length)
+		if err != nil {
+			return nil, fmt.Errorf("reading file: %w", err)
+		}
+
+		if len(data) == 0 {
+			return nil, fmt.Errorf("reading file: %w", cardErrors.ErrInvalidLength)
+		}
+
+		output = append(output, data...)
+		card.reportProgress(uint(len(output)), fileLength)
+
+		offset += uint(len(data))
+		length -= uint(len(data))
+	}
+
+	return output, nil
+}
+
+func (card *TachographCard) selectFile(ctx context.Context, name []byte) ([]byte, error) {
+	apu := buildAPDU(0x00, 0xA4, 0x02, 0x0C, name, 0)
+	rsp, err := transmit(ctx, card.smartCard, apu)
+	if err != nil {
+		return nil, fmt.Errorf("selecting file: %w", err)
+	}
+
+	return rsp, nil
+}
+
+// Tachograph cards are produced by many manufacturers,
+// so they are recognized by the presence of the tachograph application.
+func (card *TachographCard) Test() bool {
+	return card.InitCard() == nil
+}
+
+func parseTachographIdentificationFile(data []byte, doc *document.TachographDocument) error {
+	if len(data) < tachoIdentificationFileSize {
+		return cardErrors.ErrInvalidLength
+	}
+
+	doc.CardIssuingMemberState = decodeTachographNation(data[0])
+	doc.CardNumber = decodeTachographString(data[1:17])
+	doc.CardIssuingAuthorityName = decodeTachographName(data[17:53])
+	doc.CardIssueDate = decodeTachographTime(data[53:57])
+	doc.CardValidityBegin = decodeTachographTime(data[57:61])
+	doc.CardExpiryDate = decodeTachographTime(data[61:65])
+	doc.HolderSurname = decodeTachographName(data[65:101])
+	doc.HolderFirstNames = decodeTachographName(data[101:137])
+	doc.DateOfBirth = decodeTachographDate(data[137:141])
+	doc.PreferredLanguage = decodeTachographString(data[141:143])
+
+	return nil
+}
+
+func parseTachographLicenceFile(data []byte, doc *document.TachographDocument) error {
+	if len(data) < tachoLicenceFileSize {
+		return cardErrors.ErrInvalidLength
+	}
+
+	doc.DrivingLicenceIssuingAuthority = decodeTachographName(data[0:36])
+	doc.DrivingLicenceIssuingNation = decodeTachographNation(data[36])
+	doc.DrivingLicenceNumber = decodeTachographString(data[37:53])
+
+	return nil
+}
+
+// Parses driver activity data. Daily records are stored in a cyclic buffer,
+// and each record holds the length of the previous one, so records are
+// read backwards starting from the newest one.
+func parseTachographActivityFile(data []byte, doc *document.TachographDocument) error {
+	if len(data) < 4 {
+		return cardErrors.ErrInvalidLength
+	}
+
+	oldest := uint(binary.BigEndian.Uint16(data[0:]))
+	newest := uint(binary.BigEndian.Uint16(data[2:]))
+	buffer := data[4:]
+	size := uint(len(buffer))
+
+	if size == 0 {
+		return nil
+	}
+
+	if oldest >= size || newest >= size {
+		return cardErrors.ErrInvalidFormat
+	}
+
+	at := func(offset, length uint) []byte {
+		out := make([]byte, length)
+		for i := range length {
+			out[i] = buffer[(offset+i)%size]
+		}
+		return out
+	}
+
+	days := make([]document.TachographActivityDay, 0)
+	position := newest
+
+	for range size / 12 {
+		header := at(position, 12)
+		previousLength := uint(binary.BigEndian.Uint16(header[0:]))
+		recordLength := uint(binary.BigEndian.Uint16(header[2:]))
+
+		if recordLength < 12 || recordLength > size {
+			break
+		}
+
+		day := document.TachographActivityDay{
+			Date:            decodeTachographTime(header[4:8]),
+			PresenceCounter: decodeBCD(header[8:10]),
+			Distance:        uint(binary.BigEndian.Uint16(header[10:])),
+		}
+
+		changes := at(position+12, recordLength-12)
+		for i := 0; i+1 < len(changes); i += 2 {
+			day.Activities = append(day.Activities, decodeTachographActivity(binary.BigEndian.Uint16(changes[i:])))
+		}
+		day.SetDurations()
+
+		days = append(days, day)
+
+		if position == oldest || previousLength == 0 {
+			break
+		}
+
+		position = (position + size - previousLength%size) % size
+	}
+
+	for i, j := 0, len(days)-1; i < j; i, j = i+1, j-1 {
+		days[i], days[j] = days[j], days[i]
+	}
+
+	doc.ActivityDays = days
+
+	return nil
+}
+
+// Decodes ActivityChangeInfo value. Bits are laid out as 'scpaattttttttttt', where
+// s is the slot, c is the driving status, p is the card status, aa is the activity
+// and t is the time of change in minutes since 00h00.
+func decodeTachographActivity(value uint16) document.TachographActivity {
+	activity := document.TachographActivity{
+		StartMinute:  uint(value & 0x07FF),
+		CoDriver:     value&0x8000 != 0,
+		Crew:         value&0x4000 != 0,
+		CardInserted: value&0x2000 == 0,
+	}
+
+	switch (value >> 11) & 0x03 {
+	case 0:
+		activity.Activity = document.TACHO_ACTIVITY_REST
+	case 1:
+		activity.Activity = document.TACHO_ACTIVITY_AVAILABILITY
+	case 2:
+		activity.Activity = document.TACHO_ACTIVITY_WORK
+	case 3:
+		activity.Activity = document.TACHO_ACTIVITY_DRIVING
+	}
+
+	return activity
+}
+
+// Decodes TimeReal value (number of seconds since 1970-01-01 00:00 UTC) into date.
+func decodeTachographTime(data []byte) document.Date {
+	seconds := binary.BigEndian.Uint32(data)
+	if seconds == 0 {
+		return document.Date{}
+	}
+
+	return document.NewDate(time.Unix(int64(seconds), 0).UTC())
+}
+
+// Decodes BCD encoded Datef value (yyyymmdd) into date.
+// Invalid dates are kept only as text, in DD.MM.YYYY. format.
+func decodeTachographDate(data []byte) document.Date {
+	date := decodeBCD(data)
+	if len(date) != 8 || date == "00000000" {
+		return document.Date{}
+	}
+
+	t, err := time.Parse("20060102", date)
+	if err != nil {
+		return document.Date{Text: date[6:8] + "." + date[4:6] + "." + date[0:4] + "."}
+	}
+
+	return document.NewDate(t)
+}
+
+func decodeBCD(data []byte) string {
+	var out strings.Builder
+	for _, b := range data {
+		out.WriteByte('0' + b>>4)
+		out.WriteByte('0' + b&0x0F)
+	}
+
+	return out.String()
+}
+
+func decodeTachographString(data []byte) string {
+	return strings.Trim(string(data), " \x00\xFF")
+}
+
+// Decodes Name value. The first byte specifies the ISO/IEC 8859 part used to encode the name.
+func decodeTachographName(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	codePage := data[0]
+	text := data[1:]
+
+	var decoder *charmap.Charmap
+	switch codePage {
+	case 2:
+		decoder = charmap.ISO8859_2
+	case 3:
+		decoder = charmap.ISO8859_3
+	case 4:
+		decoder = charmap.ISO8859_4
+	case 5:
+		decoder = charmap.ISO8859_5
+	case 6:
+		decoder = charmap.ISO8859_6
+	case 7:
+		decoder = charmap.ISO8859_7
+	case 8:
+		decoder = charmap.ISO8859_8
+	case 9:
+		decoder = charmap.ISO8859_9
+	case 10:
+		decoder = charmap.ISO8859_10
+	case 13:
+		decoder = charmap.ISO8859_13
+	case 14:
+		decoder = charmap.ISO8859_14
+	case 15:
+		decoder = charmap.ISO8859_15
+	case 16:
+		decoder = charmap.ISO8859_16
+	default:
+		decoder = charmap.ISO8859_1
+	}
+
+	decoded, err := decoder.NewDecoder().Bytes(text)
+	if err != nil {
+		return decodeTachographString(text)
+	}
+
+	return decodeTachographString(decoded)
+}
+
+var tachographNations = map[byte]string{
+	0x01: "A", 0x02: "AL", 0x03: "AND", 0x04: "ARM", 0x05: "AZ", 0x06: "B", 0x07: "BG",
+	0x08: "BIH", 0x09: "BY", 0x0A: "CH", 0x0B: "CY", 0x0C: "CZ", 0x0D: "D", 0x0E: "DK",
+	0x0F: "E", 0x10: "EST", 0x11: "F", 0x12: "FIN", 0x13: "FL", 0x14: "FR", 0x15: "UK",
+	0x16: "GE", 0x17: "GR", 0x18: "H", 0x19: "HR", 0x1A: "I", 0x1B: "IRL", 0x1C: "IS",
+	0x1D: "KZ", 0x1E: "L", 0x1F: "LT", 0x20: "LV", 0x21: "M", 0x22: "MC", 0x23: "MD",
+	0x24: "MK", 0x25: "N", 0x26: "NL", 0x27: "P", 0x28: "PL", 0x29: "RO", 0x2A: "RSM",
+	0x2B: "RUS", 0x2C: "S", 0x2D: "SK", 0x2E: "SLO", 0x2F: "TM", 0x30: "TR", 0x31: "UA",
+	0x32: "V", 0x33: "YU", 0x34: "MNE", 0x35: "SRB", 0x36: "UZ", 0x37: "TJ",
+	0xFD: "EC", 0xFE: "EUR", 0xFF: "WLD",
+}
+
+// Decodes NationNumeric value into the distinguishing sign of the country.
+func decodeTachographNation(code byte) string {
+	nation, ok := tachographNations[code]
+	if !ok {
+		return ""
+	}
+
+	return nation
+}
@@ -0,0 +1,82 @@
+package card
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ubavic/bas-celik/document"
+)
+
+func Test_parseTachographActivityFile(t *testing.T) {
+	data := []byte{
+		0x00, 0x00, 0x00, 0x10, // oldest and newest record pointers
+		0x00, 0x00, 0x00, 0x10, 0x65, 0x53, 0xF1, 0x00, 0x00, 0x01, 0x00, 0x64, 0x19, 0x68, 0x02, 0x58,
+		0x00, 0x10, 0x00, 0x0E, 0x65, 0x55, 0x42, 0x80, 0x00, 0x02, 0x00, 0x32, 0x10, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+
+	doc := document.TachographDocument{}
+	err := parseTachographActivityFile(data, &doc)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if len(doc.ActivityDays) != 2 {
+		t.Fatalf("Expected 2 days, but got %d", len(doc.ActivityDays))
+	}
+
+	day := doc.ActivityDays[0]
+	if day.Date.Text != "14.11.2023." || day.Distance != 100 || day.PresenceCounter != "0001" {
+		t.Errorf("Unexpected first day %v", day)
+	}
+
+	if day.Total(document.TACHO_ACTIVITY_DRIVING) != 240 || day.Total(document.TACHO_ACTIVITY_REST) != 840 {
+		t.Errorf("Unexpected activities of the first day %v", day.Activities)
+	}
+
+	day = doc.ActivityDays[1]
+	if day.Date.Text != "15.11.2023." || day.Total(document.TACHO_ACTIVITY_WORK) != 24*60 {
+		t.Errorf("Unexpected second day %v", day)
+	}
+
+	err = parseTachographActivityFile([]byte{0x00, 0x30, 0x00, 0x00, 0x00}, &doc)
+	if err == nil {
+		t.Errorf("Expected error, but got nil")
+	}
+}
+
+func Test_decodeTachographName(t *testing.T) {
+	testCases := []struct {
+		data     []byte
+		expected string
+	}{
+		{[]byte{0x01, 'P', 'E', 'T', 'R', 'O', 'V', 'I', 'C', ' ', ' '}, "PETROVIC"},
+		{[]byte{0x02, 'P', 'e', 't', 'r', 'o', 'v', 'i', 0xE6, ' '}, "Petrović"},
+		{[]byte{0x05, 0xBF, 0xD5, 0xE2, 0xD0, 0xE0, ' '}, "Петар"},
+		{[]byte{}, ""},
+	}
+
+	for _, testCase := range testCases {
+		result := decodeTachographName(testCase.data)
+		if result != testCase.expected {
+			t.Errorf("Expected '%s', but got '%s'", testCase.expected, result)
+		}
+	}
+}
+
+func Test_decodeTachographDate(t *testing.T) {
+	date := decodeTachographDate([]byte{0x19, 0x87, 0x05, 0x23})
+	if !date.Time.Equal(time.Date(1987, 5, 23, 0, 0, 0, 0, time.UTC)) || date.Text != "23.05.1987." {
+		t.Errorf("Unexpected date %v (%q)", date.Time, date.Text)
+	}
+
+	date = decodeTachographDate([]byte{0x19, 0x87, 0x13, 0x23})
+	if !date.IsZero() || date.Text != "23.13.1987." {
+		t.Errorf("Expected invalid date to be kept as text, but got %v (%q)", date.Time, date.Text)
+	}
+
+	date = decodeTachographDate([]byte{0x00, 0x00, 0x00, 0x00})
+	if date != (document.Date{}) {
+		t.Errorf("Expected empty date, but got %v", date)
+	}
+}
@@ -0,0 +1,44 @@
+package tlv_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ubavic/bas-celik/card/tlv"
+)
+
+func FuzzParseRecords(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0x01, 0x00, 0x05, 0x00, 0x48, 0x65, 0x6C, 0x6C, 0x6F, 0x09, 0x00, 0x05, 0x00, 0x57, 0x6F, 0x72, 0x6C, 0x64})
+	f.Add([]byte{0x01, 0x00, 0x05, 0x00, 0x48, 0x65})
+	f.Add([]byte{0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x01})
+	f.Add([]byte{0x11, 0x06, 0xFF, 0xFF})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		records, err := tlv.ParseRecords(data)
+		if err != nil {
+			return
+		}
+
+		encoded, err := records.Encode()
+		if err != nil {
+			t.Fatalf("Encoding parsed records: %v", err)
+		}
+
+		if !bytes.Equal(encoded, data) {
+			t.Fatalf("Expected parsed records to be encoded as %X, but got %X", data, encoded)
+		}
+	})
+}
+
+func FuzzParseTLV(f *testing.F) {
+	f.Add([]byte{0x01, 0x00, 0x02, 0x00, 0x48, 0x65})
+	f.Add([]byte{0x01, 0x00, 0x00})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fields, err := tlv.ParseTLV(data)
+		if err == nil && fields == nil {
+			t.Fatalf("Expected fields for valid data")
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package tlv
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ubavic/bas-celik/card/cardErrors"
+)
+
+// Represents a single field of simple TLV-encoded data,
+// where tag and length are encoded with two bytes each (little endian).
+type Record struct {
+	Tag    uint
+	Value  []byte
+	Offset uint // Offset of the record header in the parsed data. Ignored when encoding
+}
+
+// Represents TLV-encoded data as a list of records.
+// Unlike the map returned by ParseTLV, it keeps the order of records and repeated tags.
+type Records []Record
+
+// Returned when TLV-encoded data can't be parsed.
+type ParseError struct {
+	Offset uint // Offset of the record that can't be parsed
+	Err    error
+}
+
+func (err *ParseError) Error() string {
+	return fmt.Sprintf("parsing TLV at offset %d: %v", err.Offset, err.Err)
+}
+
+func (err *ParseError) Unwrap() error {
+	return err.Err
+}
+
+// Parses TLV-encoded data into records.
+// Returns ParseError if a record header or value exceeds the data.
+// Values are not copied.
+func ParseRecords(data []byte) (Records, error) {
+	records := Records{}
+	offset := uint(0)
+
+	for offset < uint(len(data)) {
+		if uint(len(data))-offset < 4 {
+			return nil, &ParseError{Offset: offset, Err: fmt.Errorf("%w: incomplete header", cardErrors.ErrInvalidLength)}
+		}
+
+		tag := uint(binary.LittleEndian.Uint16(data[offset:]))
+		length := uint(binary.LittleEndian.Uint16(data[offset+2:]))
+
+		if length > uint(len(data))-offset-4 {
+			return nil, &ParseError{Offset: offset, Err: fmt.Errorf("%w: value of tag %d exceeds data", cardErrors.ErrInvalidLength, tag)}
+		}
+
+		records = append(records, Record{
+			Tag:    tag,
+			Value:  data[offset+4 : offset+4+length],
+			Offset: offset,
+		})
+
+		offset += 4 + length
+	}
+
+	return records, nil
+}
+
+// Encodes records into TLV data. Records are encoded in the given order.
+func (records Records) Encode() ([]byte, error) {
+	data := []byte{}
+
+	for i, record := range records {
+		if record.Tag > 0xFFFF {
+			return nil, fmt.Errorf("encoding record %d: %w: tag %d is too large", i, cardErrors.ErrInvalidFormat, record.Tag)
+		}
+
+		if len(record.Value) > 0xFFFF {
+			return nil, fmt.Errorf("encoding record %d: %w: value is too long", i, cardErrors.ErrInvalidLength)
+		}
+
+		data = binary.LittleEndian.AppendUint16(data, uint16(record.Tag))
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(record.Value)))
+		data = append(data, record.Value...)
+	}
+
+	return data, nil
+}
+
+// Returns the value of the first record with the given tag.
+func (records Records) Get(tag uint) ([]byte, bool) {
+	for _, record := range records {
+		if record.Tag == tag {
+			return record.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// Returns values of all records with the given tag, in order.
+func (records Records) GetAll(tag uint) [][]byte {
+	values := [][]byte{}
+
+	for _, record := range records {
+		if record.Tag == tag {
+			values = append(values, record.Value)
+		}
+	}
+
+	return values
+}
+
+// Returns a map of tags to values. If a tag is repeated, the last value is used.
+func (records Records) Map() map[uint][]byte {
+	m := make(map[uint][]byte, len(records))
+
+	for _, record := range records {
+		m[record.Tag] = record.Value
+	}
+
+	return m
+}
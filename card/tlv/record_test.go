@@ -0,0 +1,108 @@
+package tlv_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ubavic/bas-celik/card/cardErrors"
+	"github.com/ubavic/bas-celik/card/tlv"
+)
+
+func Test_ParseRecords(t *testing.T) {
+	data := []byte{
+		0x09, 0x00, 0x02, 0x00, 0x41, 0x42,
+		0x01, 0x00, 0x00, 0x00,
+		0x09, 0x00, 0x01, 0x00, 0x43,
+	}
+
+	records, err := tlv.ParseRecords(data)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	expected := tlv.Records{
+		{Tag: 9, Value: []byte("AB"), Offset: 0},
+		{Tag: 1, Value: []byte{}, Offset: 6},
+		{Tag: 9, Value: []byte("C"), Offset: 10},
+	}
+
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected records %v, but got %v", expected, records)
+	}
+
+	value, ok := records.Get(9)
+	if !ok || string(value) != "AB" {
+		t.Errorf("Expected first value of the tag, but got %q", value)
+	}
+
+	if values := records.GetAll(9); len(values) != 2 || string(values[1]) != "C" {
+		t.Errorf("Expected all values of the tag, but got %q", values)
+	}
+
+	if _, ok := records.Get(2); ok {
+		t.Errorf("Expected missing tag not to be found")
+	}
+
+	if m := records.Map(); string(m[9]) != "C" || len(m) != 2 {
+		t.Errorf("Expected last value in the map, but got %v", m)
+	}
+
+	encoded, err := records.Encode()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if !bytes.Equal(encoded, data) {
+		t.Errorf("Expected records to be encoded as %X, but got %X", data, encoded)
+	}
+}
+
+func Test_ParseRecordsInvalid(t *testing.T) {
+	testCases := []struct {
+		data           []byte
+		expectedOffset uint
+	}{
+		{
+			data:           []byte{0x01, 0x00, 0x05, 0x00, 0x48, 0x65},
+			expectedOffset: 0,
+		},
+		{
+			data:           []byte{0x01, 0x00, 0x01, 0x00, 0x48, 0x02, 0x00, 0x01},
+			expectedOffset: 5,
+		},
+		{
+			data:           []byte{0x01, 0x00, 0x00, 0x00, 0x02},
+			expectedOffset: 4,
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Run(fmt.Sprintf("Case %d", i), func(t *testing.T) {
+			_, err := tlv.ParseRecords(testCase.data)
+
+			var parseError *tlv.ParseError
+			if !errors.As(err, &parseError) || !errors.Is(err, cardErrors.ErrInvalidLength) {
+				t.Fatalf("Expected parse error, but got %v", err)
+			}
+
+			if parseError.Offset != testCase.expectedOffset {
+				t.Errorf("Expected error at offset %d, but got %d", testCase.expectedOffset, parseError.Offset)
+			}
+		})
+	}
+}
+
+func Test_EncodeRecordsInvalid(t *testing.T) {
+	_, err := tlv.Records{{Tag: 0x10000}}.Encode()
+	if !errors.Is(err, cardErrors.ErrInvalidFormat) {
+		t.Errorf("Expected error for large tag, but got %v", err)
+	}
+
+	_, err = tlv.Records{{Tag: 1, Value: make([]byte, 0x10000)}}.Encode()
+	if !errors.Is(err, cardErrors.ErrInvalidLength) {
+		t.Errorf("Expected error for long value, but got %v", err)
+	}
+}
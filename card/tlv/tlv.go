@@ -1,61 +1,75 @@
 package tlv
 
 import (
-	"encoding/binary"
+	"fmt"
+	"strconv"
 
 	"github.com/ubavic/bas-celik/card/cardErrors"
+	"github.com/ubavic/bas-celik/card/mapper"
 )
 
 // Parses simple TLV-encoded data and returns a map of tags to values.
 // It assumes that tag and length are encoded with two bytes each.
+// If a tag is repeated, the last value is used. Use ParseRecords to keep all values.
 func ParseTLV(data []byte) (map[uint][]byte, error) {
 	if len(data) == 0 {
 		return nil, cardErrors.ErrInvalidLength
 	}
 
-	m := make(map[uint][]byte)
-	offset := uint(0)
-
-	for {
-		tag := uint(binary.LittleEndian.Uint16(data[offset:]))
-		length := uint(binary.LittleEndian.Uint16(data[offset+2:]))
+	records, err := ParseRecords(data)
+	if err != nil {
+		return nil, err
+	}
 
-		offset += 4
+	return records.Map(), nil
+}
 
-		if offset+length > uint(len(data)) {
-			return nil, cardErrors.ErrInvalidLength
-		}
+// Assigns values from the fields map to the fields of the struct pointed by v.
+// Struct fields are mapped with the tlv tag that holds the decimal tag of the value, e.g. `tlv:"1558"`.
+// Fields whose tags are not present in the map are not changed.
+// Values that are not mapped are added to the field with the `tlv:"*"` tag, if there is one.
+// See the mapper package for other tags.
+func Unmarshal(fields map[uint][]byte, v any) error {
+	return mapper.Map(v, "tlv", fieldsSource(fields))
+}
 
-		value := data[offset : offset+length]
-		m[tag] = value
-		offset += length
+type fieldsSource map[uint][]byte
 
-		if offset >= uint(len(data)) {
-			break
-		}
+func (fields fieldsSource) Lookup(key string) ([]byte, bool, error) {
+	tag, err := parseTag(key)
+	if err != nil {
+		return nil, false, err
 	}
 
-	return m, nil
+	value, ok := fields[tag]
+	return value, ok, nil
 }
 
-// Assigns the value from the provided fields map to the target string, based on the specified tag.
-// If the tag is not present in the map, the target is set to an empty string.
-func AssignField[T comparable](fields map[T][]byte, tag T, target *string) {
-	val, ok := fields[tag]
-	if ok {
-		*target = string(val)
-	} else {
-		*target = ""
+func (fields fieldsSource) Unmapped(keys []string) (map[string][]byte, error) {
+	mapped := make(map[uint]bool, len(keys))
+	for _, key := range keys {
+		tag, err := parseTag(key)
+		if err != nil {
+			return nil, err
+		}
+		mapped[tag] = true
+	}
+
+	unmapped := map[string][]byte{}
+	for tag, value := range fields {
+		if !mapped[tag] {
+			unmapped[strconv.FormatUint(uint64(tag), 10)] = value
+		}
 	}
+
+	return unmapped, nil
 }
 
-// Assigns a boolean value from the provided fields map to the target, based on the specified tag.
-// If the tag is not present in the map or the value is not 0x31, the target is set to false.
-func AssignBoolField(fields map[uint][]byte, tag uint, target *bool) {
-	val, ok := fields[tag]
-	if ok && len(val) == 1 && val[0] == 0x31 {
-		*target = true
-	} else {
-		*target = false
+func parseTag(key string) (uint, error) {
+	tag, err := strconv.ParseUint(key, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("parsing tag %q: %w", key, err)
 	}
+
+	return uint(tag), nil
 }
@@ -32,6 +32,10 @@ func Test_parseTLV(t *testing.T) {
 			data:          []byte{0x01, 0x00, 0x05, 0x00, 0x48, 0x65},
 			expectedError: cardErrors.ErrInvalidLength,
 		},
+		{
+			data:          []byte{0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x01},
+			expectedError: cardErrors.ErrInvalidLength,
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -54,76 +58,42 @@ func Test_parseTLV(t *testing.T) {
 	}
 }
 
-func Test_AssignField(t *testing.T) {
-	t.Run(
-		"Case 1",
-		func(t *testing.T) {
-			var target string
-
-			fields := make(map[uint][]byte)
-			fields[0] = []byte{}
-
-			tlv.AssignField[uint](fields, 0, &target)
-			if target != "" {
-				t.Fatalf("Expected an empty string, but got '%s'", target)
-			}
-		},
-	)
-
-	t.Run(
-		"Case 2",
-		func(t *testing.T) {
-			var target string
-
-			fields := make(map[uint][]byte)
-			fields[5] = []byte("Hello")
-
-			tlv.AssignField[uint](fields, 5, &target)
-			if target != "Hello" {
-				t.Fatalf("Expected 'Hello', but got '%s'", target)
-			}
-		},
-	)
-
-	t.Run(
-		"Case 3",
-		func(t *testing.T) {
-			var target string
+func Test_Unmarshal(t *testing.T) {
+	var doc struct {
+		Name  string            `tlv:"1558"`
+		Date  string            `tlv:"1566" date:"dmy"`
+		Valid bool              `tlv:"1587"`
+		Other string            `tlv:"1600"`
+		Extra map[string][]byte `tlv:"*"`
+	}
+	doc.Other = "unchanged"
 
-			fields := make(map[uint][]byte)
+	fields := map[uint][]byte{
+		1558: []byte("Name"),
+		1566: []byte("05061990"),
+		1587: {0x31},
+		1590: []byte("Unmapped"),
+	}
 
-			tlv.AssignField[uint](fields, 0, &target)
-			if target != "" {
-				t.Fatalf("Expected an empty string, but got '%s'", target)
-			}
-		},
-	)
-}
+	err := tlv.Unmarshal(fields, &doc)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
 
-func Test_assignBoolField(t *testing.T) {
-	var testCases = []struct {
-		value  []byte
-		target bool
-	}{
-		{value: []byte{0x31}, target: true},
-		{value: []byte{0x01}, target: false},
-		{value: []byte{}, target: false},
+	if doc.Name != "Name" || doc.Date != "05.06.1990." || !doc.Valid || doc.Other != "unchanged" {
+		t.Errorf("Unexpected result %+v", doc)
 	}
 
-	for i, testCase := range testCases {
-		t.Run(
-			fmt.Sprintf("Case %d", i),
-			func(t *testing.T) {
-				var target bool
+	if len(doc.Extra) != 1 || string(doc.Extra["1590"]) != "Unmapped" {
+		t.Errorf("Expected single unmapped field, but got %v", doc.Extra)
+	}
 
-				fields := make(map[uint][]byte)
-				fields[0] = testCase.value
+	var invalid struct {
+		Name string `tlv:"x1"`
+	}
 
-				tlv.AssignBoolField(fields, 0, &target)
-				if target != testCase.target {
-					t.Fatalf("Expected target to be %v, but got %v", testCase.target, target)
-				}
-			},
-		)
+	err = tlv.Unmarshal(fields, &invalid)
+	if err == nil {
+		t.Errorf("Expected error for invalid tag")
 	}
 }
@@ -1,6 +1,7 @@
 package card
 
 import (
+	"context"
 	"errors"
 
 	"github.com/ubavic/bas-celik/document"
@@ -15,15 +16,27 @@ func (card *UnknownDocumentCard) Atr() Atr {
 	return card.atr
 }
 
-func (card *UnknownDocumentCard) ReadFile(_ []byte) ([]byte, error) {
+func (card *UnknownDocumentCard) ReadFile(name []byte) ([]byte, error) {
+	return card.ReadFileContext(context.Background(), name)
+}
+
+func (card *UnknownDocumentCard) ReadFileContext(_ context.Context, _ []byte) ([]byte, error) {
 	return nil, errors.New("not implemented")
 }
 
 func (card *UnknownDocumentCard) InitCard() error {
+	return card.InitCardContext(context.Background())
+}
+
+func (card *UnknownDocumentCard) InitCardContext(_ context.Context) error {
 	return nil
 }
 
 func (card *UnknownDocumentCard) ReadCard() error {
+	return card.ReadCardContext(context.Background())
+}
+
+func (card *UnknownDocumentCard) ReadCardContext(_ context.Context) error {
 	return nil
 }
 
@@ -34,3 +47,5 @@ func (card *UnknownDocumentCard) GetDocument() (document.Document, error) {
 func (card *UnknownDocumentCard) Test() bool {
 	return true
 }
+
+func (card *UnknownDocumentCard) SetReadOptions(_ ReadOptions) {}
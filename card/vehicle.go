@@ -1,16 +1,17 @@
 package card
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/ubavic/bas-celik/card/ber"
 	"github.com/ubavic/bas-celik/card/cardErrors"
 	"github.com/ubavic/bas-celik/document"
-	"github.com/ubavic/bas-celik/localization"
 )
 
 // Represents a smart card that contains a Serbian vehicle document.
 type VehicleCard struct {
+	readTracker
 	atr       Atr
 	smartCard Card
 	files     [4][]byte
@@ -49,25 +50,48 @@ var VEHICLE_ATR_4 = Atr([]byte{
 	0x73, 0x02, 0x05, 0x02, 0xD4,
 })
 
+// Three sequences of application identifiers that are selected during the initialization of the vehicle card.
+var VEHICLE_AIDS = [][3][]byte{
+	{
+		{0xA0, 0x00, 0x00, 0x01, 0x51, 0x00, 0x00},
+		{0xA0, 0x00, 0x00, 0x00, 0x77, 0x01, 0x08, 0x00, 0x07, 0x00, 0x00, 0xFE, 0x00, 0x00, 0x01, 0x00},
+		{0xA0, 0x00, 0x00, 0x00, 0x77, 0x01, 0x08, 0x00, 0x07, 0x00, 0x00, 0xFE, 0x00, 0x00, 0xAD, 0xF2},
+	},
+	{
+		{0xA0, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00},
+		{0xF3, 0x81, 0x00, 0x00, 0x02, 0x53, 0x45, 0x52, 0x56, 0x4C, 0x04, 0x02, 0x01},
+		{0xA0, 0x00, 0x00, 0x00, 0x77, 0x01, 0x08, 0x00, 0x07, 0x00, 0x00, 0xFE, 0x00, 0x00, 0xAD, 0xF2},
+	},
+	{
+		{0xA0, 0x00, 0x00, 0x00, 0x18, 0x43, 0x4D, 0x00},
+		{0xA0, 0x00, 0x00, 0x00, 0x18, 0x34, 0x14, 0x01, 0x00, 0x65, 0x56, 0x4C, 0x2D, 0x30, 0x30, 0x31},
+		{0xA0, 0x00, 0x00, 0x00, 0x18, 0x65, 0x56, 0x4C, 0x2D, 0x30, 0x30, 0x31},
+	},
+}
+
 // Initializes vehicle card by trying three different sets of commands.
 // The procedure is reverse-engineered from the official binary.
 func (card VehicleCard) InitCard() error {
+	return card.InitCardContext(context.Background())
+}
+
+func (card VehicleCard) InitCardContext(ctx context.Context) error {
 	tryToSelect := func(cmd1, cmd2, cmd3 []byte) error {
 		apu := buildAPDU(0x00, 0xA4, 0x04, 0x00, cmd1, 0)
-		rsp, err := card.smartCard.Transmit(apu)
+		rsp, err := transmit(ctx, card.smartCard, apu)
 		if err != nil {
 			return fmt.Errorf("selecting file: %w", err)
 		}
 
 		if responseOK(rsp) {
 			apu = buildAPDU(0x00, 0xA4, 0x04, 0x00, cmd2, 0)
-			_, err = card.smartCard.Transmit(apu)
+			_, err = transmit(ctx, card.smartCard, apu)
 			if err != nil {
 				return fmt.Errorf("selecting file: %w", err)
 			}
 
 			apu = buildAPDU(0x00, 0xA4, 0x04, 0x0C, cmd3, 0)
-			_, err = card.smartCard.Transmit(apu)
+			_, err = transmit(ctx, card.smartCard, apu)
 			if err != nil {
 				return fmt.Errorf("selecting file: %w", err)
 			}
@@ -78,38 +102,29 @@ func (card VehicleCard) InitCard() error {
 		}
 	}
 
-	err := tryToSelect(
-		[]byte{0xA0, 0x00, 0x00, 0x01, 0x51, 0x00, 0x00},
-		[]byte{0xA0, 0x00, 0x00, 0x00, 0x77, 0x01, 0x08, 0x00, 0x07, 0x00, 0x00, 0xFE, 0x00, 0x00, 0x01, 0x00},
-		[]byte{0xA0, 0x00, 0x00, 0x00, 0x77, 0x01, 0x08, 0x00, 0x07, 0x00, 0x00, 0xFE, 0x00, 0x00, 0xAD, 0xF2})
-	if err == nil {
-		return nil
-	}
-
-	err = tryToSelect(
-		[]byte{0xA0, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00},
-		[]byte{0xF3, 0x81, 0x00, 0x00, 0x02, 0x53, 0x45, 0x52, 0x56, 0x4C, 0x04, 0x02, 0x01},
-		[]byte{0xA0, 0x00, 0x00, 0x00, 0x77, 0x01, 0x08, 0x00, 0x07, 0x00, 0x00, 0xFE, 0x00, 0x00, 0xAD, 0xF2})
-	if err == nil {
-		return nil
-	}
-
-	err = tryToSelect(
-		[]byte{0xA0, 0x00, 0x00, 0x00, 0x18, 0x43, 0x4D, 0x00},
-		[]byte{0xA0, 0x00, 0x00, 0x00, 0x18, 0x34, 0x14, 0x01, 0x00, 0x65, 0x56, 0x4C, 0x2D, 0x30, 0x30, 0x31},
-		[]byte{0xA0, 0x00, 0x00, 0x00, 0x18, 0x65, 0x56, 0x4C, 0x2D, 0x30, 0x30, 0x31})
-	if err == nil {
-		return nil
+	var err error
+	for _, aids := range VEHICLE_AIDS {
+		err = tryToSelect(aids[0], aids[1], aids[2])
+		if err == nil {
+			return nil
+		}
 	}
 
 	return fmt.Errorf("card not responsive: %w", err)
 }
 
 func (card *VehicleCard) ReadCard() error {
+	return card.ReadCardContext(context.Background())
+}
+
+func (card *VehicleCard) ReadCardContext(ctx context.Context) error {
+	card.startRead(4)
+	defer card.endRead()
+
 	var err error
 
 	for i := byte(0); i <= 3; i++ {
-		card.files[int(i)], err = card.ReadFile([]byte{0xD0, i*0x10 + 0x01})
+		card.files[int(i)], err = card.ReadFileContext(ctx, []byte{0xD0, i*0x10 + 0x01})
 		if err != nil {
 			return fmt.Errorf("reading document %d file: %w", i, err)
 		}
@@ -134,71 +149,61 @@ func (card *VehicleCard) GetDocument() (document.Document, error) {
 		}
 	}
 
-	data.AssignFrom(&doc.RegistrationNumberOfVehicle, 0x71, 0x81)
-	data.AssignFrom(&doc.DateOfFirstRegistration, 0x71, 0x82)
-	localization.FormatDateYMD(&doc.DateOfFirstRegistration)
-	data.AssignFrom(&doc.VehicleIdNumber, 0x71, 0x8A)
-	data.AssignFrom(&doc.VehicleMass, 0x71, 0x8C)
-	data.AssignFrom(&doc.ExpiryDate, 0x71, 0x8D)
-	localization.FormatDateYMD(&doc.ExpiryDate)
-	data.AssignFrom(&doc.IssuingDate, 0x71, 0x8E)
-	localization.FormatDateYMD(&doc.IssuingDate)
-	data.AssignFrom(&doc.TypeApprovalNumber, 0x71, 0x8F)
-	data.AssignFrom(&doc.PowerWeightRatio, 0x71, 0x93)
-	data.AssignFrom(&doc.VehicleMake, 0x71, 0xA3, 0x87)
-	data.AssignFrom(&doc.VehicleType, 0x71, 0xA3, 0x88)
-	data.AssignFrom(&doc.CommercialDescription, 0x71, 0xA3, 0x89)
-	data.AssignFrom(&doc.MaximumPermissibleLadenMass, 0x71, 0xA4, 0x8B)
-	data.AssignFrom(&doc.EngineCapacity, 0x71, 0xA5, 0x90)
-	data.AssignFrom(&doc.MaximumNetPower, 0x71, 0xA5, 0x91)
-	data.AssignFrom(&doc.TypeOfFuel, 0x71, 0xA5, 0x92)
-	data.AssignFrom(&doc.NumberOfSeats, 0x71, 0xA6, 0x94)
-	data.AssignFrom(&doc.NumberOfStandingPlaces, 0x71, 0xA6, 0x95)
-	data.AssignFrom(&doc.StateIssuing, 0x71, 0x9F33)
-	data.AssignFrom(&doc.CompetentAuthority, 0x71, 0x9F35)
-	data.AssignFrom(&doc.AuthorityIssuing, 0x71, 0x9F36)
-	data.AssignFrom(&doc.UnambiguousNumber, 0x71, 0x9F38)
-	data.AssignFrom(&doc.VehicleCategory, 0x72, 0x98)
-	data.AssignFrom(&doc.NumberOfAxles, 0x72, 0x99)
-	data.AssignFrom(&doc.VehicleLoad, 0x72, 0xC4)
-	data.AssignFrom(&doc.YearOfProduction, 0x72, 0xC5)
-	data.AssignFrom(&doc.EngineIdNumber, 0x72, 0xA5, 0x9E)
-	data.AssignFrom(&doc.SerialNumber, 0x72, 0xC9)
-	data.AssignFrom(&doc.ColourOfVehicle, 0x72, 0x9F24)
-	data.AssignFrom(&doc.UsersPersonalNo, 0x72, 0xC3)
-	data.AssignFrom(&doc.OwnersPersonalNo, 0x72, 0xC2)
-
-	data.AssignFrom(&doc.OwnersSurnameOrBusinessName, 0x71, 0xA1, 0xA2, 0x83)
-	data.AssignFrom(&doc.OwnerName, 0x71, 0xA1, 0xA2, 0x84)
-	data.AssignFrom(&doc.OwnerAddress, 0x71, 0xA1, 0xA2, 0x85)
-
-	data.AssignFrom(&doc.UsersSurnameOrBusinessName, 0x71, 0xA1, 0xA9, 0x83)
-	data.AssignFrom(&doc.UsersName, 0x71, 0xA1, 0xA9, 0x84)
-	data.AssignFrom(&doc.UsersAddress, 0x71, 0xA1, 0xA9, 0x85)
-	if doc.UsersName == "" && doc.UsersSurnameOrBusinessName == "" && doc.UsersAddress == "" {
-		data.AssignFrom(&doc.UsersSurnameOrBusinessName, 0x72, 0xA1, 0xA9, 0x83)
-		data.AssignFrom(&doc.UsersName, 0x72, 0xA1, 0xA9, 0x84)
-		data.AssignFrom(&doc.UsersAddress, 0x72, 0xA1, 0xA9, 0x85)
+	err := ber.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, fmt.Errorf("assigning fields: %w", err)
 	}
 
+	assignUser(&doc, data)
+
 	return &doc, nil
 }
 
+// Assigns the user fields from a single file, so that they describe the same person:
+// from the file 71 if it holds any of the fields, and from the file 72 otherwise.
+// The fields have no mapper tags, so their values are removed from Extra.
+// The personal number of the user is stored only in the file 72, next to the one of the owner.
+func assignUser(doc *document.VehicleDocument, data ber.BER) {
+	for _, file := range []uint32{0x71, 0x72} {
+		for _, tag := range []uint32{0x83, 0x84, 0x85} {
+			delete(doc.Extra, fmt.Sprintf("%X.A1.A9.%X", file, tag))
+		}
+	}
+
+	for _, file := range []uint32{0x71, 0x72} {
+		surname, _ := data.Value(file, 0xA1, 0xA9, 0x83)
+		name, _ := data.Value(file, 0xA1, 0xA9, 0x84)
+		address, _ := data.Value(file, 0xA1, 0xA9, 0x85)
+
+		if len(surname) > 0 || len(name) > 0 || len(address) > 0 || file == 0x72 {
+			doc.UsersSurnameOrBusinessName = string(surname)
+			doc.UsersName = string(name)
+			doc.UsersAddress = string(address)
+			return
+		}
+	}
+}
+
 func (card *VehicleCard) Atr() Atr {
 	return card.atr
 }
 
 func (card *VehicleCard) ReadFile(name []byte) ([]byte, error) {
+	return card.ReadFileContext(context.Background(), name)
+}
+
+func (card *VehicleCard) ReadFileContext(ctx context.Context, name []byte) ([]byte, error) {
+	card.startFile()
 	output := make([]byte, 0)
 
-	_, err := card.selectFile(name)
+	_, err := card.selectFile(ctx, name)
 	if err != nil {
 		return nil, fmt.Errorf("selecting file: %w", err)
 	}
 
 	const headerSize = uint(0x20)
 
-	header, err := read(card.smartCard, 0, headerSize)
+	header, err := read(ctx, card.smartCard, 0, headerSize)
 	if err != nil {
 		return nil, fmt.Errorf("reading file header: %w", err)
 	}
@@ -208,14 +213,18 @@ func (card *VehicleCard) ReadFile(name []byte) ([]byte, error) {
 		return nil, fmt.Errorf("parsing file header: %w", err)
 	}
 
+	fileLength := length
+	card.reportProgress(0, fileLength)
+
 	for length > 0 {
 		toRead := min(length, 0x64)
-		data, err := read(card.smartCard, offset, toRead)
+		data, err := read(ctx, card.smartCard, offset, toRead)
 		if err != nil {
 			return nil, fmt.Errorf("reading file: %w", err)
 		}
 
 		output = append(output, data...)
+		card.reportProgress(uint(len(output)), fileLength)
 
 		offset += uint(len(data))
 		length -= uint(len(data))
@@ -234,7 +243,7 @@ func (card VehicleCard) Test() bool {
 }
 
 func parseVehicleCardFileSize(data []byte) (uint, uint, error) {
-	if len(data) < 1 {
+	if len(data) < 2 {
 		return 0, 0, cardErrors.ErrInvalidLength
 	}
 
@@ -263,10 +272,10 @@ func parseVehicleCardFileSize(data []byte) (uint, uint, error) {
 	return length, offset, nil
 }
 
-func (card *VehicleCard) selectFile(name []byte) ([]byte, error) {
+func (card *VehicleCard) selectFile(ctx context.Context, name []byte) ([]byte, error) {
 	apu := buildAPDU(0x00, 0xA4, 0x02, 0x04, name, 0)
 
-	rsp, err := card.smartCard.Transmit(apu)
+	rsp, err := transmit(ctx, card.smartCard, apu)
 	if err != nil {
 		return nil, fmt.Errorf("selecting file: %w", err)
 	}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/ubavic/bas-celik/card/ber"
 	"github.com/ubavic/bas-celik/card/cardErrors"
+	"github.com/ubavic/bas-celik/document"
 )
 
 func Test_parseVehicleCardFileSize(t *testing.T) {
@@ -18,6 +20,10 @@ func Test_parseVehicleCardFileSize(t *testing.T) {
 			data:          []byte{},
 			expectedError: cardErrors.ErrInvalidLength,
 		},
+		{
+			data:          []byte{0x78},
+			expectedError: cardErrors.ErrInvalidLength,
+		},
 		{
 			data:          []byte{0x01, 0x02, 0x03, 0x04},
 			expectedError: cardErrors.ErrInvalidLength,
@@ -63,3 +69,70 @@ func Test_parseVehicleCardFileSize(t *testing.T) {
 	}
 
 }
+
+func Test_VehicleCardUser(t *testing.T) {
+	user := func(file uint32, fields ...ber.BER) ber.BER {
+		return ber.NewConstructed(file, ber.NewConstructed(0xA1, ber.NewConstructed(0xA9, fields...)))
+	}
+
+	testCases := []struct {
+		name     string
+		file71   ber.BER
+		file72   ber.BER
+		expected [3]string
+	}{
+		{
+			name:     "file 71",
+			file71:   user(0x71, ber.NewPrimitive(0x83, []byte("PETROVIĆ"))),
+			file72:   user(0x72, ber.NewPrimitive(0x84, []byte("MARKO")), ber.NewPrimitive(0x85, []byte("NIŠ"))),
+			expected: [3]string{"PETROVIĆ", "", ""},
+		},
+		{
+			name:     "file 72",
+			file71:   user(0x71, ber.NewPrimitive(0x83, []byte{})),
+			file72:   user(0x72, ber.NewPrimitive(0x84, []byte("MARKO")), ber.NewPrimitive(0x85, []byte("NIŠ"))),
+			expected: [3]string{"", "MARKO", "NIŠ"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			card := VehicleCard{}
+			for i, file := range []ber.BER{testCase.file71, testCase.file72, ber.NewConstructed(0x73), ber.NewConstructed(0x74)} {
+				data, err := ber.NewConstructed(0, file).Encode()
+				if err != nil {
+					t.Fatalf("Unexpected error %v", err)
+				}
+				card.files[i] = data
+			}
+
+			doc, err := card.GetDocument()
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			vehicleDoc := doc.(*document.VehicleDocument)
+			got := [3]string{vehicleDoc.UsersSurnameOrBusinessName, vehicleDoc.UsersName, vehicleDoc.UsersAddress}
+			if got != testCase.expected {
+				t.Errorf("Expected user %v, but got %v", testCase.expected, got)
+			}
+
+			if len(vehicleDoc.Extra) != 0 {
+				t.Errorf("Expected user values not to be kept in Extra, but got %v", vehicleDoc.Extra)
+			}
+		})
+	}
+}
+
+func Fuzz_parseVehicleCardFileSize(f *testing.F) {
+	f.Add([]byte{0x78, 0x0E, 0x4F, 0x0C, 0xA0, 0x00, 0x00, 0x00, 0x18, 0x65, 0x56, 0x4C, 0x2D, 0x30, 0x30, 0x31, 0x72, 0x27})
+	f.Add([]byte{0x01, 0x01, 0x01, 0x00, 0x80})
+	f.Add([]byte{0x78})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, offset, err := parseVehicleCardFileSize(data)
+		if err == nil && offset >= uint(len(data)) {
+			t.Fatalf("Expected offset inside data, but got %d", offset)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package document
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ubavic/bas-celik/localization"
+)
+
+// Layout of the dates in JSON (ISO 8601).
+const jsonDateLayout = "2006-01-02"
+
+// Layout of the display text of the dates created from time.Time.
+const displayDateLayout = "02.01.2006."
+
+// Represents a date read from the card.
+// Time holds the parsed date (in UTC), and Text holds the date as shown in the GUI and PDF, e.g. "23.05.1987.".
+// Time is zero if the date is not available on the card or it can't be parsed.
+// In JSON, dates are encoded in the ISO 8601 format (or as null if Time is zero).
+type Date struct {
+	Time time.Time
+	Text string
+}
+
+// Creates the date from time. Zero time creates the empty date.
+func NewDate(t time.Time) Date {
+	if t.IsZero() {
+		return Date{}
+	}
+
+	return Date{Time: t, Text: t.Format(displayDateLayout)}
+}
+
+// Parses the date in the DDMMYYYY format, as stored on ID and medical cards.
+// Years starting with zero denote that the date is not available.
+func ParseDateDMY(value string) Date {
+	t, err := time.Parse("02012006", value)
+	if err != nil || t.Year() < 1000 {
+		t = time.Time{}
+	}
+
+	localization.FormatDate(&value)
+	return Date{Time: t, Text: value}
+}
+
+// Parses the date in the YYYYMMDD format, as stored on vehicle and tachograph cards.
+func ParseDateYMD(value string) Date {
+	t, _ := time.Parse("20060102", value)
+	localization.FormatDateYMD(&value)
+	return Date{Time: t, Text: value}
+}
+
+// Parses the date as shown in the documents, e.g. "23.05.1987." or "3.4.2025.".
+func ParseDateText(text string) Date {
+	t, _ := time.Parse("2.1.2006.", text)
+	return Date{Time: t, Text: text}
+}
+
+// Returns the display text of the date.
+func (date Date) String() string {
+	return date.Text
+}
+
+func (date Date) IsZero() bool {
+	return date.Time.IsZero()
+}
+
+func (date Date) MarshalJSON() ([]byte, error) {
+	if date.Time.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(date.Time.Format(jsonDateLayout))
+}
+
+func (date *Date) UnmarshalJSON(data []byte) error {
+	var value *string
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		return err
+	}
+
+	if value == nil {
+		*date = Date{}
+		return nil
+	}
+
+	t, err := time.Parse(jsonDateLayout, *value)
+	if err != nil {
+		return fmt.Errorf("parsing date: %w", err)
+	}
+
+	*date = NewDate(t)
+	return nil
+}
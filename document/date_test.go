@@ -0,0 +1,124 @@
+package document_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ubavic/bas-celik/document"
+	"github.com/xuri/excelize/v2"
+)
+
+func Test_ParseDate(t *testing.T) {
+	testCases := []struct {
+		date         document.Date
+		expectedTime time.Time
+		expectedText string
+	}{
+		{
+			date:         document.ParseDateDMY("23051987"),
+			expectedTime: time.Date(1987, 5, 23, 0, 0, 0, 0, time.UTC),
+			expectedText: "23.05.1987.",
+		},
+		{
+			date:         document.ParseDateDMY("01010001"),
+			expectedText: "Nije dostupan",
+		},
+		{
+			date:         document.ParseDateDMY("00001950"),
+			expectedText: "00.00.1950.",
+		},
+		{
+			date:         document.ParseDateYMD("20200115"),
+			expectedTime: time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC),
+			expectedText: "15.01.2020",
+		},
+		{
+			date:         document.ParseDateText("3.4.2025."),
+			expectedTime: time.Date(2025, 4, 3, 0, 0, 0, 0, time.UTC),
+			expectedText: "3.4.2025.",
+		},
+		{
+			date: document.ParseDateYMD(""),
+		},
+	}
+
+	for _, testCase := range testCases {
+		if !testCase.date.Time.Equal(testCase.expectedTime) || testCase.date.Text != testCase.expectedText {
+			t.Errorf("Expected %v (%q), but got %v (%q)", testCase.expectedTime, testCase.expectedText, testCase.date.Time, testCase.date.Text)
+		}
+	}
+}
+
+func Test_DateJson(t *testing.T) {
+	doc := document.VehicleDocument{
+		IssuingDate: document.ParseDateYMD("20200115"),
+		ExpiryDate:  document.ParseDateYMD("0"),
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	var decoded map[string]any
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if decoded["IssuingDate"] != "2020-01-15" || decoded["ExpiryDate"] != nil {
+		t.Errorf("Expected ISO 8601 date and null, but got %v and %v", decoded["IssuingDate"], decoded["ExpiryDate"])
+	}
+
+	var parsed document.VehicleDocument
+	err = json.Unmarshal(data, &parsed)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if !parsed.IssuingDate.Time.Equal(doc.IssuingDate.Time) || parsed.IssuingDate.Text != "15.01.2020." || !parsed.ExpiryDate.IsZero() {
+		t.Errorf("Unexpected decoded dates %v and %v", parsed.IssuingDate, parsed.ExpiryDate)
+	}
+}
+
+func Test_DateExcel(t *testing.T) {
+	doc := document.VehicleDocument{IssuingDate: document.ParseDateYMD("20200115")}
+
+	data, _, err := doc.BuildExcel()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	rows, err := f.GetRows("Sheet1", excelize.Options{RawCellValue: true})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	for i, row := range rows {
+		if row[0] != "IssuingDate" {
+			continue
+		}
+
+		// Excel stores dates as number of days since 1899-12-30
+		if len(row) < 2 || row[1] != "43845" {
+			t.Errorf("Expected date cell, but got %v", row)
+		}
+
+		value, _ := f.GetCellValue("Sheet1", fmt.Sprintf("B%d", i+1))
+		if value != "15.01.2020" {
+			t.Errorf("Expected formatted date, but got %q", value)
+		}
+
+		return
+	}
+
+	t.Errorf("Date row not found")
+}
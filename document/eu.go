@@ -0,0 +1,297 @@
+package document
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/signintech/gopdf"
+	"github.com/xuri/excelize/v2"
+)
+
+// Field of the vehicle document labeled with the harmonised code from the EU Directive 1999/37/EC.
+// Value holds string, Date, or Quantity for numeric fields.
+// National fields that don't have the harmonised code have the empty code.
+type EUField struct {
+	Code  string `json:",omitempty"`
+	Name  string // English name of the field
+	Field string // Name of the VehicleDocument field
+	Value any
+}
+
+// Harmonised codes of VehicleDocument fields, in the order of the directive, followed by national fields.
+// The owner is the holder of the registration certificate (C.1),
+// and the user is the person who may use the vehicle by virtue of a legal right other than ownership (C.3).
+var euFields = []struct {
+	field   string
+	code    string
+	name    string
+	unit    Unit
+	numeric bool
+}{
+	{field: "RegistrationNumberOfVehicle", code: "A", name: "Registration number"},
+	{field: "DateOfFirstRegistration", code: "B", name: "Date of first registration"},
+	{field: "OwnersSurnameOrBusinessName", code: "C.1.1", name: "Holder's surname or business name"},
+	{field: "OwnerName", code: "C.1.2", name: "Holder's other names or initials"},
+	{field: "OwnerAddress", code: "C.1.3", name: "Holder's address"},
+	{field: "UsersSurnameOrBusinessName", code: "C.3.1", name: "User's surname or business name"},
+	{field: "UsersName", code: "C.3.2", name: "User's other names or initials"},
+	{field: "UsersAddress", code: "C.3.3", name: "User's address"},
+	{field: "VehicleMake", code: "D.1", name: "Make"},
+	{field: "VehicleType", code: "D.2", name: "Type"},
+	{field: "CommercialDescription", code: "D.3", name: "Commercial description"},
+	{field: "VehicleIdNumber", code: "E", name: "Vehicle identification number"},
+	{field: "MaximumPermissibleLadenMass", code: "F.1", name: "Technically permissible maximum laden mass", unit: UnitKilogram, numeric: true},
+	{field: "VehicleMass", code: "G", name: "Mass of the vehicle in service", unit: UnitKilogram, numeric: true},
+	{field: "ExpiryDate", code: "H", name: "Period of validity"},
+	{field: "IssuingDate", code: "I", name: "Date of the registration"},
+	{field: "VehicleCategory", code: "J", name: "Vehicle category"},
+	{field: "TypeApprovalNumber", code: "K", name: "Type-approval number"},
+	{field: "NumberOfAxles", code: "L", name: "Number of axles", numeric: true},
+	{field: "EngineCapacity", code: "P.1", name: "Capacity", unit: UnitCubicCentimetre, numeric: true},
+	{field: "MaximumNetPower", code: "P.2", name: "Maximum net power", unit: UnitKilowatt, numeric: true},
+	{field: "TypeOfFuel", code: "P.3", name: "Type of fuel or power source"},
+	{field: "EngineRatedSpeed", code: "P.4", name: "Rated speed"},
+	{field: "EngineIdNumber", code: "P.5", name: "Engine identification number"},
+	{field: "PowerWeightRatio", code: "Q", name: "Power/weight ratio", unit: UnitKilowattPerKilogram, numeric: true},
+	{field: "ColourOfVehicle", code: "R", name: "Colour of the vehicle"},
+	{field: "NumberOfSeats", code: "S.1", name: "Number of seats, including the driver's seat", numeric: true},
+	{field: "NumberOfStandingPlaces", code: "S.2", name: "Number of standing places", numeric: true},
+	{field: "StateIssuing", name: "Issuing state"},
+	{field: "AuthorityIssuing", name: "Issuing authority"},
+	{field: "CompetentAuthority", name: "Competent authority"},
+	{field: "UnambiguousNumber", name: "Registration certificate number"},
+	{field: "SerialNumber", name: "Serial number"},
+	{field: "OwnersPersonalNo", name: "Holder's personal or company number"},
+	{field: "UsersPersonalNo", name: "User's personal or company number"},
+	{field: "VehicleLoad", name: "Load capacity", unit: UnitKilogram, numeric: true},
+	{field: "YearOfProduction", name: "Year of production"},
+	{field: "HomologationMark", name: "Homologation mark"},
+}
+
+// Returns fields of the document labeled with the harmonised codes, followed by national fields.
+func (doc *VehicleDocument) EUFields() []EUField {
+	value := reflect.ValueOf(doc).Elem()
+	fields := make([]EUField, 0, len(euFields))
+
+	for _, euField := range euFields {
+		field := EUField{Code: euField.code, Name: euField.name, Field: euField.field}
+
+		fieldValue := value.FieldByName(euField.field).Interface()
+		if text, ok := fieldValue.(string); ok && euField.numeric {
+			field.Value = ParseQuantity(text, euField.unit)
+		} else {
+			field.Value = fieldValue
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// Formats the value for the PDF. Quantities that can't be parsed are empty.
+func (field EUField) Text() string {
+	switch value := field.Value.(type) {
+	case string:
+		return value
+	case Date:
+		return value.Text
+	case Quantity:
+		return value.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// Vehicle document exported with the harmonised codes of the EU Directive 1999/37/EC.
+// Exported files contain fields labeled with codes and English names.
+type EUVehicleDocument struct {
+	Vehicle *VehicleDocument
+}
+
+func (doc *EUVehicleDocument) BuildJson() ([]byte, error) {
+	return json.Marshal(doc.Vehicle.EUFields())
+}
+
+// Creates an Excel file with codes, names and values in the first three columns.
+// Dates and numeric fields are set as date and number cells.
+func (doc *EUVehicleDocument) BuildExcel() ([]byte, string, error) {
+	fileName := doc.Vehicle.formatFilename() + "_eu.xlsx"
+
+	f := excelize.NewFile()
+	widths := []struct {
+		column string
+		width  float64
+	}{{"A", 10}, {"B", 45}, {"C", 50}}
+
+	for _, width := range widths {
+		err := f.SetColWidth("Sheet1", width.column, width.column, width.width)
+		if err != nil {
+			return nil, fileName, err
+		}
+	}
+
+	f.SetCellValue("Sheet1", "A1", "Code")
+	f.SetCellValue("Sheet1", "B1", "Field")
+	f.SetCellValue("Sheet1", "C1", "Value")
+
+	for i, field := range doc.Vehicle.EUFields() {
+		row := i + 2
+		f.SetCellValue("Sheet1", fmt.Sprintf("A%d", row), field.Code)
+		f.SetCellValue("Sheet1", fmt.Sprintf("B%d", row), field.Name)
+
+		var value any = field.Value
+		if quantity, ok := value.(Quantity); ok {
+			value = ""
+			if quantity.Valid {
+				value = quantity.Value
+			}
+		}
+
+		setCell(f, "Sheet1", fmt.Sprintf("C%d", row), value)
+	}
+
+	xlsx, err := writeExcelFile(f)
+	return xlsx, fileName, err
+}
+
+func (doc *EUVehicleDocument) BuildPdf() (data []byte, fileName string, retErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch x := r.(type) {
+			case error:
+				retErr = x
+			default:
+				retErr = errors.New("unknown panic")
+			}
+		}
+	}()
+
+	pdf := gopdf.GoPdf{}
+	pdf.Start(gopdf.Config{PageSize: *gopdf.PageSizeA4})
+
+	pdf.AddPage()
+
+	err := pdf.AddTTFFontData("liberationsans", fontRegular)
+	if err != nil {
+		panic(fmt.Errorf("loading font: %w", err))
+	}
+
+	err = pdf.AddTTFFontDataWithOption("liberationsans", fontBold, gopdf.TtfOption{Style: gopdf.Bold})
+	if err != nil {
+		panic(fmt.Errorf("loading font: %w", err))
+	}
+
+	const leftMargin = 28
+	const rightMargin = 535
+	const textLeftMargin = 38
+	const pageBottom = 800
+
+	columns := []float64{textLeftMargin, 80, 310}
+	const valueWidth = rightMargin - 310
+
+	cell := func(s string) {
+		err := pdf.Cell(nil, s)
+		if err != nil {
+			panic(fmt.Errorf("putting text: %w", err))
+		}
+	}
+
+	setFont := func(style string, size float64) {
+		err := pdf.SetFont("liberationsans", style, size)
+		if err != nil {
+			panic(fmt.Errorf("setting font: %w", err))
+		}
+	}
+
+	newLine := func(height float64) {
+		if pdf.GetY()+height > pageBottom {
+			pdf.AddPage()
+			pdf.SetXY(textLeftMargin, 40)
+			return
+		}
+		pdf.SetXY(textLeftMargin, pdf.GetY()+height)
+	}
+
+	section := func(name string) {
+		newLine(6)
+		pdf.Line(leftMargin, pdf.GetY(), rightMargin, pdf.GetY())
+		newLine(8)
+		setFont("B", 12)
+		cell(name)
+		setFont("", 10)
+		newLine(22)
+	}
+
+	row := func(code, name, value string) {
+		texts, err := pdf.SplitTextWithWordWrap(value, valueWidth)
+		if err != nil && err != gopdf.ErrEmptyString {
+			panic(fmt.Errorf("splitting text: %w", err))
+		}
+
+		if len(texts) == 0 {
+			texts = []string{""}
+		}
+
+		if pdf.GetY()+float64(len(texts))*12 > pageBottom {
+			pdf.AddPage()
+			pdf.SetXY(textLeftMargin, 40)
+		}
+
+		pdf.SetX(columns[0])
+		setFont("B", 10)
+		cell(code)
+		setFont("", 10)
+		pdf.SetX(columns[1])
+		cell(name)
+
+		for i, text := range texts {
+			pdf.SetX(columns[2])
+			cell(text)
+			if i < len(texts)-1 {
+				pdf.SetY(pdf.GetY() + 12)
+			}
+		}
+
+		newLine(16)
+	}
+
+	setFont("B", 20)
+	pdf.SetXY(textLeftMargin, 35)
+	cell("Vehicle registration certificate")
+
+	pdf.SetLineWidth(1.5)
+	pdf.SetLineType("solid")
+	pdf.Line(leftMargin, 64, rightMargin, 64)
+
+	pdf.SetXY(textLeftMargin, 72)
+	setFont("", 10)
+	cell("Fields are labeled with the harmonised codes of the Council Directive 1999/37/EC.")
+	pdf.SetLineWidth(0.5)
+	newLine(14)
+
+	section("Harmonised fields")
+	nationalSection := false
+	for _, field := range doc.Vehicle.EUFields() {
+		if len(field.Code) == 0 && !nationalSection {
+			section("National fields")
+			nationalSection = true
+		}
+
+		row(field.Code, field.Name, field.Text())
+	}
+
+	fileName = doc.Vehicle.formatFilename() + "_eu.pdf"
+
+	pdf.SetInfo(gopdf.PdfInfo{
+		Title:        doc.Vehicle.VehicleMake + " " + doc.Vehicle.CommercialDescription,
+		Author:       "Baš Čelik",
+		Subject:      "Vehicle registration certificate",
+		CreationDate: time.Now(),
+	})
+
+	return pdf.GetBytesPdf(), fileName, nil
+}
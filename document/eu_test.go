@@ -0,0 +1,129 @@
+package document_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ubavic/bas-celik/document"
+	"github.com/xuri/excelize/v2"
+)
+
+func Test_EUFields(t *testing.T) {
+	doc := documentVehicleQuantities
+	doc.RegistrationNumberOfVehicle = "BG123AA"
+	doc.DateOfFirstRegistration = document.ParseDateYMD("20150310")
+
+	testCases := []struct {
+		code          string
+		expectedField string
+		expectedText  string
+	}{
+		{code: "A", expectedField: "RegistrationNumberOfVehicle", expectedText: "BG123AA"},
+		{code: "B", expectedField: "DateOfFirstRegistration", expectedText: "10.03.2015"},
+		{code: "F.1", expectedField: "MaximumPermissibleLadenMass", expectedText: "1830 kg"},
+		{code: "P.1", expectedField: "EngineCapacity", expectedText: "1598 cm³"},
+		{code: "S.2", expectedField: "NumberOfStandingPlaces", expectedText: ""},
+	}
+
+	fields := doc.EUFields()
+	for _, testCase := range testCases {
+		found := false
+		for _, field := range fields {
+			if field.Code != testCase.code {
+				continue
+			}
+
+			found = true
+			if field.Field != testCase.expectedField {
+				t.Errorf("Expected field %s for code %s but got %s", testCase.expectedField, testCase.code, field.Field)
+			}
+
+			if field.Text() != testCase.expectedText {
+				t.Errorf("Expected '%s' for code %s but got '%s'", testCase.expectedText, testCase.code, field.Text())
+			}
+		}
+
+		if !found {
+			t.Errorf("Code %s not found", testCase.code)
+		}
+	}
+}
+
+func Test_EUVehicleDocumentJson(t *testing.T) {
+	doc := document.EUVehicleDocument{Vehicle: &documentVehicleQuantities}
+
+	data, err := doc.BuildJson()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	var fields []struct {
+		Code  string
+		Name  string
+		Value any
+	}
+
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	for _, field := range fields {
+		if field.Code == "P.2" {
+			if field.Name != "Maximum net power" || field.Value != float64(77) {
+				t.Errorf("Expected numeric maximum net power but got %v", field)
+			}
+			return
+		}
+	}
+
+	t.Errorf("Code P.2 not found")
+}
+
+func Test_EUVehicleDocumentExcel(t *testing.T) {
+	doc := document.EUVehicleDocument{Vehicle: &documentVehicleQuantities}
+
+	data, fileName, err := doc.BuildExcel()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if !strings.HasSuffix(fileName, "_eu.xlsx") {
+		t.Errorf("Unexpected file name %s", fileName)
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	rows, err := f.GetRows("Sheet1")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	for _, row := range rows {
+		if row[0] == "P.1" {
+			if len(row) < 3 || row[1] != "Capacity" || row[2] != "1598" {
+				t.Errorf("Expected capacity row but got %v", row)
+			}
+			return
+		}
+	}
+
+	t.Errorf("Code P.1 not found")
+}
+
+func Test_EUVehicleDocumentPdf(t *testing.T) {
+	setDocumentConfigFromLocalFiles(t)
+
+	for _, vehicle := range []*document.VehicleDocument{&documentVehicle1, &documentVehicle2, &documentVehicleQuantities} {
+		doc := document.EUVehicleDocument{Vehicle: vehicle}
+		_, _, err := doc.BuildPdf()
+		if err != nil {
+			t.Errorf("Unexpected error %v", err)
+		}
+	}
+}
@@ -11,6 +11,16 @@ import (
 )
 
 func CreateExcel(document any) ([]byte, error) {
+	f, err := createExcelFile(document)
+	if err != nil {
+		return nil, err
+	}
+
+	return writeExcelFile(f)
+}
+
+// Creates an Excel file with string, boolean and date fields of the document in the first sheet.
+func createExcelFile(document any) (*excelize.File, error) {
 	structType := reflect.TypeOf(document)
 	structVal := reflect.ValueOf(document)
 
@@ -37,9 +47,20 @@ func CreateExcel(document any) ([]byte, error) {
 		currentRow += 1
 	}
 
+	putDate := func(label string, date Date) {
+		f.SetCellValue("Sheet1", fmt.Sprintf("A%d", currentRow), label)
+		setCell(f, "Sheet1", fmt.Sprintf("B%d", currentRow), date)
+		currentRow += 1
+	}
+
 	fields := reflect.VisibleFields(structType)
 
 	for _, field := range fields {
+		if field.Type == reflect.TypeFor[Date]() {
+			putDate(field.Name, structVal.FieldByName(field.Name).Interface().(Date))
+			continue
+		}
+
 		switch field.Type.Kind() {
 		case reflect.String:
 			putData(field.Name, structVal.FieldByName(field.Name).String())
@@ -49,9 +70,50 @@ func CreateExcel(document any) ([]byte, error) {
 		}
 	}
 
+	return f, nil
+}
+
+// Sets the value of the cell. Dates are set as date cells, or as text if the date is not available.
+func setCell(f *excelize.File, sheet, cell string, value any) {
+	date, ok := value.(Date)
+	if !ok {
+		f.SetCellValue(sheet, cell, value)
+		return
+	}
+
+	if date.IsZero() {
+		f.SetCellValue(sheet, cell, date.Text)
+		return
+	}
+
+	dateFormat := "dd.mm.yyyy"
+	style, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		f.SetCellValue(sheet, cell, date.Text)
+		return
+	}
+
+	f.SetCellValue(sheet, cell, date.Time)
+	f.SetCellStyle(sheet, cell, cell, style)
+}
+
+// Appends the row with the label and the value after the last row of the first sheet.
+func appendExcelRow(f *excelize.File, label string, value any) error {
+	rows, err := f.GetRows("Sheet1")
+	if err != nil {
+		return err
+	}
+
+	row := len(rows) + 1
+	f.SetCellValue("Sheet1", fmt.Sprintf("A%d", row), label)
+	setCell(f, "Sheet1", fmt.Sprintf("B%d", row), value)
+	return nil
+}
+
+func writeExcelFile(f *excelize.File) ([]byte, error) {
 	buffer := bytes.Buffer{}
 
-	err = f.Write(&buffer)
+	err := f.Write(&buffer)
 	if err != nil {
 		return nil, err
 	}
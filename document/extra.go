@@ -0,0 +1,66 @@
+package document
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"maps"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Raw values of the card fields that are not mapped to any document field.
+// Keys are tags of the fields (decimal for TLV files, hexadecimal addresses like "72.A5.9F" for BER files).
+// Extra fields preserve data when the layout of the card changes.
+type Extra map[string][]byte
+
+// Implemented by documents that preserve unmapped card fields.
+type ExtraHolder interface {
+	GetExtra() Extra
+}
+
+// Returns keys of the fields, sorted.
+func (extra Extra) Keys() []string {
+	return slices.Sorted(maps.Keys(extra))
+}
+
+// Returns the value of the field as text if it is printable, and as hexadecimal string otherwise.
+func (extra Extra) Text(key string) string {
+	value := extra[key]
+	if text, ok := printable(value); ok {
+		return text
+	}
+
+	return "0x" + hex.EncodeToString(value)
+}
+
+type extraValue struct {
+	Value string `json:"value"`
+	Text  string `json:"text,omitempty"`
+}
+
+// Marshals fields as objects with the hexadecimal value, and the text if the value is printable.
+func (extra Extra) MarshalJSON() ([]byte, error) {
+	values := make(map[string]extraValue, len(extra))
+	for key, value := range extra {
+		text, _ := printable(value)
+		values[key] = extraValue{Value: hex.EncodeToString(value), Text: text}
+	}
+
+	return json.Marshal(values)
+}
+
+func printable(value []byte) (string, bool) {
+	if len(value) == 0 || !utf8.Valid(value) {
+		return "", false
+	}
+
+	text := string(value)
+	for _, r := range text {
+		if !unicode.IsPrint(r) {
+			return "", false
+		}
+	}
+
+	return text, true
+}
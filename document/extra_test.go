@@ -0,0 +1,67 @@
+package document_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/ubavic/bas-celik/document"
+)
+
+func Test_Extra(t *testing.T) {
+	extra := document.Extra{
+		"1585":     []byte("Text"),
+		"72.A5.9F": {0x00, 0xFF},
+		"1590":     {},
+	}
+
+	expectedKeys := []string{"1585", "1590", "72.A5.9F"}
+	if !slices.Equal(extra.Keys(), expectedKeys) {
+		t.Errorf("Expected keys %v, but got %v", expectedKeys, extra.Keys())
+	}
+
+	testCases := map[string]string{
+		"1585":     "Text",
+		"72.A5.9F": "0x00ff",
+		"1590":     "0x",
+	}
+
+	for key, expected := range testCases {
+		if text := extra.Text(key); text != expected {
+			t.Errorf("Expected text %q for %s, but got %q", expected, key, text)
+		}
+	}
+
+	data, err := json.Marshal(document.VehicleDocument{Extra: extra})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	var decoded struct {
+		Extra map[string]map[string]string
+	}
+
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if decoded.Extra["1585"]["value"] != "54657874" || decoded.Extra["1585"]["text"] != "Text" {
+		t.Errorf("Unexpected printable field %v", decoded.Extra["1585"])
+	}
+
+	if _, ok := decoded.Extra["72.A5.9F"]["text"]; ok {
+		t.Errorf("Expected binary field without text, but got %v", decoded.Extra["72.A5.9F"])
+	}
+
+	data, err = json.Marshal(document.VehicleDocument{})
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	var empty map[string]any
+	_ = json.Unmarshal(data, &empty)
+	if _, ok := empty["Extra"]; ok {
+		t.Errorf("Expected empty extra fields to be omitted")
+	}
+}
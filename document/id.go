@@ -21,41 +21,49 @@ const ID_TYPE_IDENTITY_FOREIGNER = "IF"
 const ID_TYPE_RESIDENCE_PERMIT = "RP"
 
 // Represents a document stored on a Serbian ID card.
+// Struct tags describe where fields are stored on the card (see the mapper package).
+// Tags of StatusOfForeigner and AddressLabel are not known, so the fields stay empty
+// and their values are kept in Extra.
 type IdDocument struct {
 	Portrait             image.Image
-	DocRegNo             string
-	DocumentType         string
-	IssuingDate          string
-	ExpiryDate           string
-	IssuingAuthority     string
-	DocumentSerialNumber string
-	ChipSerialNumber     string
-	DocumentName         string
-	PersonalNumber       string
-	Surname              string
-	GivenName            string
-	ParentGivenName      string
-	Sex                  string
-	PlaceOfBirth         string
-	CommunityOfBirth     string
-	StateOfBirth         string
-	StateOfBirthCode     string
-	DateOfBirth          string
+	DocRegNo             string `tlv:"1546"`
+	DocumentType         string `tlv:"1547"`
+	IssuingDate          Date   `tlv:"1549" date:"dmy"`
+	ExpiryDate           Date   `tlv:"1550" date:"dmy"`
+	IssuingAuthority     string `tlv:"1551"`
+	DocumentSerialNumber string `tlv:"1548"`
+	ChipSerialNumber     string `tlv:"1681"`
+	DocumentName         string `tlv:"1682"`
+	PersonalNumber       string `tlv:"1558"`
+	Surname              string `tlv:"1559"`
+	GivenName            string `tlv:"1560"`
+	ParentGivenName      string `tlv:"1561"`
+	Sex                  string `tlv:"1562"`
+	PlaceOfBirth         string `tlv:"1563"`
+	CommunityOfBirth     string `tlv:"1564"`
+	StateOfBirth         string `tlv:"1565"`
+	StateOfBirthCode     string `tlv:"1567"`
+	DateOfBirth          Date   `tlv:"1566" date:"dmy"`
 	StatusOfForeigner    string
-	NationalityFull      string
-	PurposeOfStay        string
-	ENote                string
-	State                string
-	Community            string
-	Place                string
-	Street               string
-	HouseNumber          string
-	HouseLetter          string
-	Entrance             string
-	Floor                string
-	ApartmentNumber      string
-	AddressDate          string
+	NationalityFull      string `tlv:"1583"`
+	PurposeOfStay        string `tlv:"1683"`
+	ENote                string `tlv:"1684"`
+	State                string `tlv:"1568"`
+	Community            string `tlv:"1569"`
+	Place                string `tlv:"1570"`
+	Street               string `tlv:"1571"`
+	HouseNumber          string `tlv:"1572"`
+	HouseLetter          string `tlv:"1573"`
+	Entrance             string `tlv:"1574"`
+	Floor                string `tlv:"1575"`
+	ApartmentNumber      string `tlv:"1578"`
+	AddressDate          Date   `tlv:"1580" date:"dmy"`
 	AddressLabel         string
+	Extra                Extra `tlv:"*" json:",omitempty"`
+}
+
+func (doc *IdDocument) GetExtra() Extra {
+	return doc.Extra
 }
 
 func (doc *IdDocument) GetFullName() string {
@@ -145,18 +153,26 @@ func (doc *IdDocument) BuildPdf() (data []byte, fileName string, retErr error) {
 
 func (doc *IdDocument) BuildJson() ([]byte, error) {
 	var bs bytes.Buffer
-	err := jpeg.Encode(&bs, doc.Portrait, &jpeg.Options{Quality: 100})
-	if err != nil {
-		return nil, fmt.Errorf("creating json: %w", err)
+	if doc.Portrait != nil {
+		err := jpeg.Encode(&bs, doc.Portrait, &jpeg.Options{Quality: 100})
+		if err != nil {
+			return nil, fmt.Errorf("creating json: %w", err)
+		}
 	}
 
 	type Alias IdDocument
 	return json.Marshal(&struct {
 		Portrait string
 		*Alias
+		Validity Validity
+		JMBG     []JMBGCheck `json:",omitempty"`
+		MRZ      MRZ
 	}{
 		Portrait: base64.StdEncoding.EncodeToString(bs.Bytes()),
 		Alias:    (*Alias)(doc),
+		Validity: doc.ValidityAt(time.Now()),
+		JMBG:     doc.CheckJMBG(),
+		MRZ:      doc.MRZ(),
 	})
 }
 
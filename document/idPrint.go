@@ -131,10 +131,10 @@ func (ipw *IdPdfWriter) printRegularId() {
 	ipw.putData("Prezime:", ipw.doc.Surname)
 	ipw.putData("Ime:", ipw.doc.GivenName)
 	ipw.putData("Ime jednog roditelja:", ipw.doc.ParentGivenName)
-	ipw.putData("Datum rođenja:", ipw.doc.DateOfBirth)
+	ipw.putData("Datum rođenja:", ipw.doc.DateOfBirth.Text)
 	ipw.putData("Mesto rođenja,\nopština i država:", ipw.doc.GetFullPlaceOfBirth())
 	ipw.putData("Prebivalište:", ipw.doc.GetFullAddress(true))
-	ipw.putData("Datum promene adrese:", ipw.doc.AddressDate)
+	ipw.putData("Datum promene adrese:", ipw.doc.AddressDate.Text)
 	ipw.putData("JMBG:", ipw.doc.PersonalNumber)
 	ipw.putData("Pol:", ipw.doc.Sex)
 
@@ -148,8 +148,9 @@ func (ipw *IdPdfWriter) printRegularId() {
 	ipw.moveY(9)
 	ipw.putData("Dokument izdaje:", ipw.doc.IssuingAuthority)
 	ipw.putData("Broj dokumenta:", ipw.doc.DocRegNo)
-	ipw.putData("Datum izdavanja:", ipw.doc.IssuingDate)
-	ipw.putData("Važi do:", ipw.doc.ExpiryDate)
+	ipw.putData("Datum izdavanja:", ipw.doc.IssuingDate.Text)
+	ipw.putData("Važi do:", ipw.doc.ExpiryDate.Text)
+	ipw.putData("MRZ:", ipw.doc.MRZ().String())
 
 	ipw.moveY(-8.67)
 	ipw.line(0)
@@ -251,10 +252,10 @@ func (ipw *IdPdfWriter) printForeignerId() {
 	ipw.putData("Prezime:", ipw.doc.Surname)
 	ipw.putData("Ime:", ipw.doc.GivenName)
 	ipw.putData("Državljanstvo:", ipw.doc.NationalityFull)
-	ipw.putData("Datum rođenja:", ipw.doc.DateOfBirth)
+	ipw.putData("Datum rođenja:", ipw.doc.DateOfBirth.Text)
 	ipw.putData("Osnov boravka:", ipw.doc.PurposeOfStay)
 	ipw.putData("Prebivalište:", localization.JoinWithComma(ipw.doc.State, ipw.doc.GetFullAddress(true)))
-	ipw.putData("Datum promene adrese:", ipw.doc.AddressDate)
+	ipw.putData("Datum promene adrese:", ipw.doc.AddressDate.Text)
 	ipw.putData("Evidencijski broj\nstranca:", ipw.doc.PersonalNumber)
 	ipw.putData("Pol:", ipw.doc.Sex)
 
@@ -268,8 +269,9 @@ func (ipw *IdPdfWriter) printForeignerId() {
 	ipw.moveY(9)
 	ipw.putData("Dokument izdaje:", ipw.doc.IssuingAuthority)
 	ipw.putData("Broj dokumenta:", ipw.doc.DocRegNo)
-	ipw.putData("Datum izdavanja:", ipw.doc.IssuingDate)
-	ipw.putData("Važi do:", ipw.doc.ExpiryDate)
+	ipw.putData("Datum izdavanja:", ipw.doc.IssuingDate.Text)
+	ipw.putData("Važi do:", ipw.doc.ExpiryDate.Text)
+	ipw.putData("MRZ:", ipw.doc.MRZ().String())
 
 	ipw.moveY(-8.67)
 	ipw.line(0)
@@ -369,10 +371,10 @@ func (ipw *IdPdfWriter) printResidencePermit() {
 	ipw.putData("Prezime:", ipw.doc.Surname)
 	ipw.putData("Ime:", ipw.doc.GivenName)
 	ipw.putData("Državljanstvo:", ipw.doc.NationalityFull)
-	ipw.putData("Datum rođenja:", ipw.doc.DateOfBirth)
+	ipw.putData("Datum rođenja:", ipw.doc.DateOfBirth.Text)
 	ipw.putData("Mesto rođenja,\nopština i država:", ipw.doc.GetFullPlaceOfBirth())
 	ipw.putData("Prebivalište:", ipw.doc.GetFullAddress(true))
-	ipw.putData("Datum promene adrese:", ipw.doc.AddressDate)
+	ipw.putData("Datum promene adrese:", ipw.doc.AddressDate.Text)
 	ipw.putData("Evidencijski broj\nstranca:", ipw.doc.PersonalNumber)
 	ipw.putData("Pol:", ipw.doc.Sex)
 	ipw.putData("Osnov boravka:", ipw.doc.PurposeOfStay)
@@ -389,8 +391,9 @@ func (ipw *IdPdfWriter) printResidencePermit() {
 	ipw.putData("Naziv dokumenta:", ipw.doc.DocumentName)
 	ipw.putData("Dokument izdaje:", ipw.doc.IssuingAuthority)
 	ipw.putData("Broj dokumenta:", ipw.doc.DocRegNo)
-	ipw.putData("Datum izdavanja:", ipw.doc.IssuingDate)
-	ipw.putData("Važi do:", ipw.doc.ExpiryDate)
+	ipw.putData("Datum izdavanja:", ipw.doc.IssuingDate.Text)
+	ipw.putData("Važi do:", ipw.doc.ExpiryDate.Text)
+	ipw.putData("MRZ:", ipw.doc.MRZ().String())
 
 	ipw.moveY(-8.67)
 	ipw.line(0)
@@ -0,0 +1,208 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Represents the unique master citizen number (JMBG) in the DDMMYYYRRBBBK format:
+// date of birth (DDMMYYY), political region (RR), unique number (BBB) and the checksum (K).
+type JMBG string
+
+var ErrInvalidJMBG = errors.New("invalid JMBG")
+
+const (
+	SexMale   = "M"
+	SexFemale = "F"
+)
+
+// Names of political regions, by the first digit of the region number.
+var jmbgRegions = [10]string{
+	"Strani državljani",
+	"Bosna i Hercegovina",
+	"Crna Gora",
+	"Hrvatska",
+	"Makedonija",
+	"Slovenija",
+	"Privremeni boravak",
+	"Centralna Srbija",
+	"Vojvodina",
+	"Kosovo i Metohija",
+}
+
+// Validates the format, the date of birth and the mod 11 checksum.
+func (jmbg JMBG) Validate() error {
+	if len(jmbg) != 13 {
+		return fmt.Errorf("%w: expected 13 digits, got %d characters", ErrInvalidJMBG, len(jmbg))
+	}
+
+	for _, c := range []byte(jmbg) {
+		if c < '0' || c > '9' {
+			return fmt.Errorf("%w: expected only digits", ErrInvalidJMBG)
+		}
+	}
+
+	_, err := jmbg.BirthDate()
+	if err != nil {
+		return err
+	}
+
+	if jmbg.checksum() != jmbg[12]-'0' {
+		return fmt.Errorf("%w: checksum doesn't match", ErrInvalidJMBG)
+	}
+
+	return nil
+}
+
+// Computes the control digit from the first 12 digits.
+func (jmbg JMBG) checksum() byte {
+	sum := 0
+	for i := range 6 {
+		sum += (7 - i) * (int(jmbg[i]-'0') + int(jmbg[i+6]-'0'))
+	}
+
+	m := 11 - sum%11
+	if m > 9 {
+		return 0
+	}
+
+	return byte(m)
+}
+
+// Decodes the date of birth from the last three digits of the year.
+// Values from 800 to 999 denote years 1800 to 1999, and values from 000 to 799 denote years 2000 to 2799.
+func (jmbg JMBG) BirthDate() (time.Time, error) {
+	if len(jmbg) < 7 {
+		return time.Time{}, fmt.Errorf("%w: too short", ErrInvalidJMBG)
+	}
+
+	value := string(jmbg[:7])
+	if value[4] >= '8' {
+		value = value[:4] + "1" + value[4:]
+	} else {
+		value = value[:4] + "2" + value[4:]
+	}
+
+	t, err := time.Parse("02012006", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid date of birth", ErrInvalidJMBG)
+	}
+
+	return t, nil
+}
+
+// Returns the name of the political region, or an empty string if the number is too short.
+func (jmbg JMBG) Region() string {
+	if len(jmbg) < 9 || jmbg[7] < '0' || jmbg[7] > '9' {
+		return ""
+	}
+
+	return jmbgRegions[jmbg[7]-'0']
+}
+
+// Returns SexMale or SexFemale, or an empty string if the number is too short.
+func (jmbg JMBG) Sex() string {
+	if len(jmbg) < 12 || jmbg[9] < '0' || jmbg[9] > '9' {
+		return ""
+	}
+
+	if jmbg[9] < '5' {
+		return SexMale
+	}
+
+	return SexFemale
+}
+
+// Result of the JMBG check against the other fields of the document.
+type JMBGCheck struct {
+	Field      string // Name of the document field that holds the JMBG
+	JMBG       JMBG
+	Error      string   `json:",omitempty"` // Reason why the JMBG is not valid. Empty for valid JMBG
+	BirthDate  Date     // Decoded date of birth
+	Region     string   // Decoded political region
+	Sex        string   // Decoded sex (SexMale or SexFemale)
+	Mismatches []string `json:",omitempty"` // Names of document fields that don't match the decoded values
+}
+
+// Checks if the JMBG is valid and all checks pass.
+func (check JMBGCheck) OK() bool {
+	return len(check.Error) == 0 && len(check.Mismatches) == 0
+}
+
+// Implemented by documents that contain JMBG.
+type JMBGChecker interface {
+	CheckJMBG() []JMBGCheck
+}
+
+// Validates the JMBG and compares it with the date of birth and the sex from the card.
+// Zero date and empty sex are not compared.
+func NewJMBGCheck(field string, jmbg JMBG, dateOfBirth Date, sex string) JMBGCheck {
+	check := JMBGCheck{Field: field, JMBG: jmbg}
+
+	err := jmbg.Validate()
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	birthDate, _ := jmbg.BirthDate()
+	check.BirthDate = NewDate(birthDate)
+	check.Region = jmbg.Region()
+	check.Sex = jmbg.Sex()
+
+	if !dateOfBirth.IsZero() && !dateOfBirth.Time.Equal(birthDate) {
+		check.Mismatches = append(check.Mismatches, "DateOfBirth")
+	}
+
+	if cardSex := parseSex(sex); cardSex != "" && cardSex != check.Sex {
+		check.Mismatches = append(check.Mismatches, "Sex")
+	}
+
+	return check
+}
+
+// Parses the sex written on the card, in Latin or Cyrillic script.
+func parseSex(sex string) string {
+	switch {
+	case strings.HasPrefix(sex, "M"), strings.HasPrefix(sex, "М"):
+		return SexMale
+	case strings.HasPrefix(sex, "F"), strings.HasPrefix(sex, "Ž"), strings.HasPrefix(sex, "Ж"):
+		return SexFemale
+	default:
+		return ""
+	}
+}
+
+func (doc *IdDocument) CheckJMBG() []JMBGCheck {
+	if len(doc.PersonalNumber) == 0 {
+		return nil
+	}
+
+	return []JMBGCheck{NewJMBGCheck("PersonalNumber", JMBG(doc.PersonalNumber), doc.DateOfBirth, doc.Sex)}
+}
+
+func (doc *MedicalDocument) CheckJMBG() []JMBGCheck {
+	if len(doc.PersonalNumber) == 0 {
+		return nil
+	}
+
+	return []JMBGCheck{NewJMBGCheck("PersonalNumber", JMBG(doc.PersonalNumber), doc.DateOfBirth, doc.Gender)}
+}
+
+// Personal numbers of the owner and the user are checked only if they have 13 digits,
+// since companies are identified with shorter registration numbers.
+func (doc *VehicleDocument) CheckJMBG() []JMBGCheck {
+	checks := []JMBGCheck{}
+
+	if len(doc.OwnersPersonalNo) == 13 {
+		checks = append(checks, NewJMBGCheck("OwnersPersonalNo", JMBG(doc.OwnersPersonalNo), Date{}, ""))
+	}
+
+	if len(doc.UsersPersonalNo) == 13 {
+		checks = append(checks, NewJMBGCheck("UsersPersonalNo", JMBG(doc.UsersPersonalNo), Date{}, ""))
+	}
+
+	return checks
+}
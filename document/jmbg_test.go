@@ -0,0 +1,99 @@
+package document_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ubavic/bas-celik/document"
+)
+
+func Test_JMBG(t *testing.T) {
+	testCases := []struct {
+		jmbg           document.JMBG
+		expectedError  error
+		expectedDate   time.Time
+		expectedRegion string
+		expectedSex    string
+	}{
+		{
+			jmbg:           "2305987711232",
+			expectedDate:   time.Date(1987, 5, 23, 0, 0, 0, 0, time.UTC),
+			expectedRegion: "Centralna Srbija",
+			expectedSex:    document.SexMale,
+		},
+		{
+			jmbg:           "0101005805504",
+			expectedDate:   time.Date(2005, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedRegion: "Vojvodina",
+			expectedSex:    document.SexFemale,
+		},
+		{
+			jmbg:          "2305987711231",
+			expectedError: document.ErrInvalidJMBG,
+		},
+		{
+			jmbg:          "3102987711232",
+			expectedError: document.ErrInvalidJMBG,
+		},
+		{
+			jmbg:          "23059877112",
+			expectedError: document.ErrInvalidJMBG,
+		},
+		{
+			jmbg:          "23059877112AB",
+			expectedError: document.ErrInvalidJMBG,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(string(testCase.jmbg), func(t *testing.T) {
+			err := testCase.jmbg.Validate()
+			if !errors.Is(err, testCase.expectedError) {
+				t.Fatalf("Expected error %v, but got %v", testCase.expectedError, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			date, _ := testCase.jmbg.BirthDate()
+			if !date.Equal(testCase.expectedDate) || testCase.jmbg.Region() != testCase.expectedRegion || testCase.jmbg.Sex() != testCase.expectedSex {
+				t.Errorf("Unexpected decoded values %v, %s, %s", date, testCase.jmbg.Region(), testCase.jmbg.Sex())
+			}
+		})
+	}
+}
+
+func Test_CheckJMBG(t *testing.T) {
+	doc := document.IdDocument{
+		PersonalNumber: "2305987711232",
+		DateOfBirth:    document.ParseDateDMY("23051987"),
+		Sex:            "M",
+	}
+
+	checks := doc.CheckJMBG()
+	if len(checks) != 1 || !checks[0].OK() || checks[0].Region != "Centralna Srbija" {
+		t.Errorf("Expected valid JMBG, but got %+v", checks)
+	}
+
+	doc.DateOfBirth = document.ParseDateDMY("24051987")
+	doc.Sex = "Ž"
+	checks = doc.CheckJMBG()
+	if len(checks) != 1 || !slices.Equal(checks[0].Mismatches, []string{"DateOfBirth", "Sex"}) {
+		t.Errorf("Expected mismatched date and sex, but got %+v", checks)
+	}
+
+	medical := document.MedicalDocument{PersonalNumber: "2305987711231", Gender: "Женско"}
+	checks = medical.CheckJMBG()
+	if len(checks) != 1 || checks[0].OK() || len(checks[0].Error) == 0 {
+		t.Errorf("Expected invalid JMBG, but got %+v", checks)
+	}
+
+	vehicle := document.VehicleDocument{OwnersPersonalNo: "12345678", UsersPersonalNo: "0101005805504"}
+	checks = vehicle.CheckJMBG()
+	if len(checks) != 1 || checks[0].Field != "UsersPersonalNo" || !checks[0].OK() {
+		t.Errorf("Expected only the user's JMBG to be checked, but got %+v", checks)
+	}
+}
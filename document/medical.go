@@ -29,48 +29,54 @@ var ErrInvalidInsuranceNo = errors.New("invalid insurance number length")
 var ErrNoSubmatchFound = errors.New("no submatch found")
 
 // Represents a document stored on a Serbian public medical insurance card.
+// Struct tags describe where fields are stored on the card (see the mapper package).
 type MedicalDocument struct {
-	InsurerName            string
-	InsurerID              string
-	CardId                 string
-	DateOfIssue            string
-	DateOfExpiry           string
+	InsurerName            string `tlv:"1553" enc:"utf16le"`
+	InsurerID              string `tlv:"1554"`
+	CardId                 string `tlv:"1555"`
+	DateOfIssue            Date   `tlv:"1557" date:"dmy"`
+	DateOfExpiry           Date   `tlv:"1558" date:"dmy"`
 	ChipSerialNumber       string
-	PrintLanguage          string
-	PersonalNumber         string
-	FamilyNameLatin        string
-	GivenNameLatin         string
-	ParentNameLatin        string
-	FamilyName             string
-	GivenName              string
-	ParentName             string
+	PrintLanguage          string `tlv:"1560"`
+	PersonalNumber         string `tlv:"1604"`
+	FamilyNameLatin        string `tlv:"1571" enc:"utf16le"`
+	GivenNameLatin         string `tlv:"1573" enc:"utf16le"`
+	ParentNameLatin        string `tlv:"1602" enc:"utf16le"`
+	FamilyName             string `tlv:"1570" enc:"utf16le"`
+	GivenName              string `tlv:"1572" enc:"utf16le"`
+	ParentName             string `tlv:"1601" enc:"utf16le"`
 	Gender                 string
-	InsurantNumber         string
-	DateOfBirth            string
-	Apartment              string
-	Number                 string
-	Street                 string
-	Place                  string
-	Municipality           string
-	Country                string
-	ValidUntil             string
-	PermanentlyValid       bool
-	CarrierGivenNameLatin  string
-	CarrierFamilyNameLatin string
-	CarrierGivenName       string
-	CarrierFamilyName      string
-	CarrierIdNumber        string
-	CarrierInsurantNumber  string
-	CarrierFamilyMember    bool
-	CarrierRelationship    string
-	InsuranceBasisRZZO     string
-	InsuranceStartDate     string
-	InsuranceDescription   string
-	TaxpayerName           string
-	TaxpayerResidence      string
+	InsurantNumber         string `tlv:"1569"`
+	DateOfBirth            Date   `tlv:"1574" date:"dmy"`
+	Apartment              string `tlv:"1612" enc:"utf16le"`
+	Number                 string `tlv:"1610" enc:"utf16le"`
+	Street                 string `tlv:"1605" enc:"utf16le"`
+	Place                  string `tlv:"1608" enc:"utf16le"`
+	Municipality           string `tlv:"1607" enc:"utf16le"`
+	Country                string `tlv:"1626" enc:"utf16le"`
+	ValidUntil             Date   `tlv:"1586" date:"dmy"`
+	PermanentlyValid       bool   `tlv:"1587"`
+	CarrierGivenNameLatin  string `tlv:"1623" enc:"utf16le"`
+	CarrierFamilyNameLatin string `tlv:"1621" enc:"utf16le"`
+	CarrierGivenName       string `tlv:"1622" enc:"utf16le"`
+	CarrierFamilyName      string `tlv:"1620" enc:"utf16le"`
+	CarrierIdNumber        string `tlv:"1618"`
+	CarrierInsurantNumber  string `tlv:"1619"`
+	CarrierFamilyMember    bool   `tlv:"1617"`
+	CarrierRelationship    string `tlv:"1616" enc:"utf16le"`
+	InsuranceBasisRZZO     string `tlv:"1614"`
+	InsuranceStartDate     Date   `tlv:"1624" date:"dmy"`
+	InsuranceDescription   string `tlv:"1615" enc:"utf16le"`
+	TaxpayerName           string `tlv:"1630" enc:"utf16le"`
+	TaxpayerResidence      string `tlv:"1631" enc:"utf16le"`
 	TaxpayerNumber         string
-	TaxpayerIdNumber       string
-	TaxpayerActivityCode   string
+	TaxpayerIdNumber       string `tlv:"1632|1633"`
+	TaxpayerActivityCode   string `tlv:"1634"`
+	Extra                  Extra  `tlv:"*" json:",omitempty"`
+}
+
+func (doc *MedicalDocument) GetExtra() Extra {
+	return doc.Extra
 }
 
 func (doc *MedicalDocument) GetFullName() string {
@@ -189,7 +195,7 @@ func (doc *MedicalDocument) BuildPdf() (data []byte, fileName string, retErr err
 
 	putData("Презиме:", doc.FamilyName+" ("+doc.FamilyNameLatin+")")
 
-	putData("Датум рођења:", doc.DateOfBirth)
+	putData("Датум рођења:", doc.DateOfBirth.Text)
 
 	putData("Место, општина и држава:", doc.GetFullPlaceAddress())
 
@@ -205,11 +211,11 @@ func (doc *MedicalDocument) BuildPdf() (data []byte, fileName string, retErr err
 
 	section("Подаци о картици здравственог осигурања")
 
-	putData("Датум издавања:", doc.DateOfIssue)
+	putData("Датум издавања:", doc.DateOfIssue.Text)
 
-	putData("Датум важења:", doc.DateOfExpiry)
+	putData("Датум важења:", doc.DateOfExpiry.Text)
 
-	putData("Оверена до:", doc.ValidUntil)
+	putData("Оверена до:", doc.ValidUntil.Text)
 
 	putData("Трајно оверена:", localization.FormatYesNo(doc.PermanentlyValid, localization.SrCyrillic))
 
@@ -231,7 +237,7 @@ func (doc *MedicalDocument) BuildPdf() (data []byte, fileName string, retErr err
 
 	putData("Основ осигурања:", doc.InsuranceBasisRZZO)
 
-	putData("Датум почетка осигурања:", doc.InsuranceStartDate)
+	putData("Датум почетка осигурања:", doc.InsuranceStartDate.Text)
 
 	putData("Опис:", doc.InsuranceDescription)
 
@@ -260,7 +266,16 @@ func (doc *MedicalDocument) BuildPdf() (data []byte, fileName string, retErr err
 }
 
 func (doc *MedicalDocument) BuildJson() ([]byte, error) {
-	return json.Marshal(doc)
+	type Alias MedicalDocument
+	return json.Marshal(&struct {
+		*Alias
+		Validity Validity
+		JMBG     []JMBGCheck `json:",omitempty"`
+	}{
+		Alias:    (*Alias)(doc),
+		Validity: doc.ValidityAt(time.Now()),
+		JMBG:     doc.CheckJMBG(),
+	})
 }
 
 func (doc *MedicalDocument) BuildExcel() ([]byte, string, error) {
@@ -299,7 +314,7 @@ func (doc *MedicalDocument) UpdateValidUntilDateFromRfzo() error {
 		return fmt.Errorf("parsing response: %w", err)
 	}
 
-	doc.ValidUntil = date
+	doc.ValidUntil = ParseDateText(date)
 
 	return nil
 }
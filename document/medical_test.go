@@ -17,13 +17,13 @@ var documentMedical2 = document.MedicalDocument{
 	Place:                  "Подгорица",
 	Country:                "Црна Гора",
 	PersonalNumber:         "01019950900200",
-	DateOfBirth:            "01.01.1995",
+	DateOfBirth:            document.ParseDateText("01.01.1995."),
 	CarrierGivenNameLatin:  "Petar",
 	CarrierFamilyNameLatin: "Petrović",
 	CarrierGivenName:       "Петар",
 	CarrierFamilyName:      "Петровић",
 	InsurantNumber:         "12345678",
-	InsuranceStartDate:     "29.03.2014",
+	InsuranceStartDate:     document.ParseDateText("29.03.2014."),
 	CardId:                 "12345678901",
 }
 var documentMedical3 = document.MedicalDocument{
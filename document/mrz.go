@@ -0,0 +1,279 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ubavic/bas-celik/localization"
+)
+
+// Number of characters in each line of the TD1 machine readable zone.
+const mrzLineLength = 30
+
+var ErrInvalidMRZ = errors.New("invalid MRZ")
+
+// Represents the TD1 machine readable zone (ICAO Doc 9303, part 5), printed on the back of ID cards.
+// Each of the three lines has 30 characters.
+type MRZ [3]string
+
+// Transliteration of the letters with diacritics (ICAO Doc 9303, part 3).
+var mrzTransliteration = strings.NewReplacer(
+	"Č", "C", "Ć", "C", "Š", "S", "Ž", "Z", "Đ", "D",
+)
+
+// Returns the MRZ with lines separated with new lines.
+func (mrz MRZ) String() string {
+	return strings.Join(mrz[:], "\n")
+}
+
+// Parses the MRZ from the text, such as the output of the MRZ scanner or the typed text.
+// Lines can be separated with new lines or spaces. Letters are converted to uppercase.
+func ParseMRZ(text string) (MRZ, error) {
+	lines := strings.Fields(strings.ToUpper(text))
+	if len(lines) != 3 {
+		return MRZ{}, fmt.Errorf("%w: expected 3 lines, got %d", ErrInvalidMRZ, len(lines))
+	}
+
+	mrz := MRZ{}
+	for i, line := range lines {
+		if len(line) != mrzLineLength {
+			return MRZ{}, fmt.Errorf("%w: line %d has %d characters instead of %d", ErrInvalidMRZ, i+1, len(line), mrzLineLength)
+		}
+
+		for _, c := range []byte(line) {
+			if mrzCharValue(c) < 0 {
+				return MRZ{}, fmt.Errorf("%w: line %d contains invalid character %q", ErrInvalidMRZ, i+1, c)
+			}
+		}
+
+		mrz[i] = line
+	}
+
+	return mrz, nil
+}
+
+// Checks that each of the three lines has 30 characters, so the fields can be extracted.
+func (mrz MRZ) checkLength() error {
+	for i, line := range mrz {
+		if len(line) != mrzLineLength {
+			return fmt.Errorf("%w: line %d has %d characters instead of %d", ErrInvalidMRZ, i+1, len(line), mrzLineLength)
+		}
+	}
+
+	return nil
+}
+
+// Verifies the length of the lines, check digits of the document number, the dates and the composite check digit.
+func (mrz MRZ) Validate() error {
+	err := mrz.checkLength()
+	if err != nil {
+		return err
+	}
+
+	checks := []struct {
+		name  string
+		data  string
+		digit byte
+	}{
+		{"document number", mrz[0][5:14], mrz[0][14]},
+		{"date of birth", mrz[1][0:6], mrz[1][6]},
+		{"expiry date", mrz[1][8:14], mrz[1][14]},
+		{"composite", mrz[0][5:30] + mrz[1][0:7] + mrz[1][8:15] + mrz[1][18:29], mrz[1][29]},
+	}
+
+	for _, check := range checks {
+		// Check digit of the long document number is placed in the optional data.
+		if check.digit == '<' && check.name == "document number" {
+			continue
+		}
+
+		if mrzCheckDigit(check.data) != check.digit {
+			return fmt.Errorf("%w: %s check digit doesn't match", ErrInvalidMRZ, check.name)
+		}
+	}
+
+	return nil
+}
+
+// Generates the MRZ from the data read from the card.
+// The optional data field holds the personal number (JMBG).
+// Nationality is known only for Serbian citizens, and it is filled with "<" for other documents.
+func (doc *IdDocument) MRZ() MRZ {
+	documentCode := "ID"
+	nationality := "SRB"
+	switch doc.DocumentType {
+	case ID_TYPE_IDENTITY_FOREIGNER:
+		documentCode = "IF"
+		nationality = "<<<"
+	case ID_TYPE_RESIDENCE_PERMIT:
+		documentCode = "IR"
+		nationality = "<<<"
+	}
+
+	docNumber := mrzField(doc.DocRegNo)
+	optionalData := mrzField(doc.PersonalNumber)
+	var docNumberCheck byte
+	if len(docNumber) > 9 {
+		optionalData = docNumber[9:] + string(mrzCheckDigit(docNumber)) + "<" + optionalData
+		docNumber = docNumber[:9]
+		docNumberCheck = '<'
+	} else {
+		docNumber = mrzPad(docNumber, 9)
+		docNumberCheck = mrzCheckDigit(docNumber)
+	}
+
+	line1 := mrzPad(documentCode+"SRB"+docNumber+string(docNumberCheck)+optionalData, mrzLineLength)
+
+	dateOfBirth := mrzDate(doc.DateOfBirth)
+	expiryDate := mrzDate(doc.ExpiryDate)
+	sex := parseSex(doc.Sex)
+	if sex == "" {
+		sex = "<"
+	}
+
+	line2 := dateOfBirth + string(mrzCheckDigit(dateOfBirth)) +
+		sex +
+		expiryDate + string(mrzCheckDigit(expiryDate)) +
+		nationality
+	line2 = mrzPad(line2, mrzLineLength-1)
+	line2 += string(mrzCheckDigit(line1[5:30] + line2[0:7] + line2[8:15] + line2[18:29]))
+
+	line3 := mrzPad(mrzField(doc.Surname)+"<<"+mrzField(doc.GivenName), mrzLineLength)
+
+	return MRZ{line1, line2, line3}
+}
+
+// Compares the MRZ with the data read from the card.
+// Returns the names of the document fields that don't match, or "CheckDigits" if check digits are not valid.
+// If the lines don't have the correct length, none of the fields match.
+func (doc *IdDocument) CompareMRZ(mrz MRZ) []string {
+	if mrz.checkLength() != nil {
+		return []string{"CheckDigits", "DocumentType", "DocRegNo", "PersonalNumber", "DateOfBirth", "Sex", "ExpiryDate", "Surname", "GivenName"}
+	}
+
+	mismatches := []string{}
+
+	if mrz.Validate() != nil {
+		mismatches = append(mismatches, "CheckDigits")
+	}
+
+	expected := doc.MRZ()
+	fields := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"DocumentType", mrz[0][0:2], expected[0][0:2]},
+		{"DocRegNo", mrzDocumentNumber(mrz), mrzDocumentNumber(expected)},
+		{"PersonalNumber", mrzOptionalData(mrz), mrzOptionalData(expected)},
+		{"DateOfBirth", mrz[1][0:6], expected[1][0:6]},
+		{"Sex", mrz[1][7:8], expected[1][7:8]},
+		{"ExpiryDate", mrz[1][8:14], expected[1][8:14]},
+		{"Surname", mrzName(mrz, 0), mrzName(expected, 0)},
+		{"GivenName", mrzName(mrz, 1), mrzName(expected, 1)},
+	}
+
+	for _, field := range fields {
+		if field.got != field.expected {
+			mismatches = append(mismatches, field.name)
+		}
+	}
+
+	return mismatches
+}
+
+// Returns the document number, including the part placed in the optional data.
+func mrzDocumentNumber(mrz MRZ) string {
+	number := mrz[0][5:14]
+	if mrz[0][14] == '<' {
+		rest, _, _ := strings.Cut(mrz[0][15:], "<")
+		if len(rest) > 0 {
+			number += rest[:len(rest)-1]
+		}
+	}
+
+	return strings.TrimRight(number, "<")
+}
+
+// Returns the optional data without the part of the long document number.
+func mrzOptionalData(mrz MRZ) string {
+	data := mrz[0][15:]
+	if mrz[0][14] == '<' {
+		_, data, _ = strings.Cut(data, "<")
+	}
+
+	return strings.Trim(data, "<")
+}
+
+// Returns the primary (0) or the secondary (1) identifier from the third line.
+func mrzName(mrz MRZ, part int) string {
+	names := strings.SplitN(mrz[2], "<<", 2)
+	if part >= len(names) {
+		return ""
+	}
+
+	return strings.Trim(names[part], "<")
+}
+
+// Transliterates the value to the Latin script without diacritics.
+// Letters are converted to uppercase, spaces and hyphens are replaced with "<", and other characters are omitted.
+func mrzField(value string) string {
+	value = strings.ToUpper(localization.CyrillicToLatin(value))
+	value = mrzTransliteration.Replace(value)
+
+	var builder strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			builder.WriteRune(r)
+		case r == ' ', r == '-', r == ',':
+			builder.WriteByte('<')
+		}
+	}
+
+	return builder.String()
+}
+
+// Pads the value with "<" to the given length, or truncates it.
+func mrzPad(value string, length int) string {
+	if len(value) > length {
+		return value[:length]
+	}
+
+	return value + strings.Repeat("<", length-len(value))
+}
+
+// Formats the date as YYMMDD. The unknown date is filled with "<".
+func mrzDate(date Date) string {
+	if date.IsZero() {
+		return "<<<<<<"
+	}
+
+	return date.Time.Format("060102")
+}
+
+// Computes the check digit with the repeating 7, 3, 1 weights.
+func mrzCheckDigit(data string) byte {
+	weights := [3]int{7, 3, 1}
+	sum := 0
+	for i, c := range []byte(data) {
+		sum += weights[i%3] * max(mrzCharValue(c), 0)
+	}
+
+	return byte('0' + sum%10)
+}
+
+// Returns the value of the character used in check digit computation, or -1 for invalid characters.
+func mrzCharValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10
+	case c == '<':
+		return 0
+	default:
+		return -1
+	}
+}
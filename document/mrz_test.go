@@ -0,0 +1,133 @@
+package document_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ubavic/bas-celik/document"
+)
+
+var documentIdMRZ = document.IdDocument{
+	DocumentType:   document.ID_TYPE_ID,
+	DocRegNo:       "012345678",
+	PersonalNumber: "2305987711232",
+	Surname:        "Ђурђевић-Чолић",
+	GivenName:      "Љубиша Жарко",
+	Sex:            "М",
+	DateOfBirth:    document.NewDate(time.Date(1987, 5, 23, 0, 0, 0, 0, time.UTC)),
+	ExpiryDate:     document.NewDate(time.Date(2031, 3, 14, 0, 0, 0, 0, time.UTC)),
+}
+
+func Test_MRZ(t *testing.T) {
+	expected := document.MRZ{
+		"IDSRB01234567842305987711232<<",
+		"8705231M3103142SRB<<<<<<<<<<<4",
+		"DURDEVIC<COLIC<<LJUBISA<ZARKO<",
+	}
+
+	mrz := documentIdMRZ.MRZ()
+	if mrz != expected {
+		t.Errorf("Expected\n%s\nbut got\n%s", expected, mrz)
+	}
+
+	err := mrz.Validate()
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func Test_ParseMRZ(t *testing.T) {
+	testCases := []struct {
+		text          string
+		expectedError error
+	}{
+		{
+			text: "I<UTOD231458907<<<<<<<<<<<<<<<\n7408122F1204159UTO<<<<<<<<<<<6\nERIKSSON<<ANNA<MARIA<<<<<<<<<<",
+		},
+		{
+			text: "i<utoD231458907<<<<<<<<<<<<<<< 7408122F1204159UTO<<<<<<<<<<<6 ERIKSSON<<ANNA<MARIA<<<<<<<<<<\n",
+		},
+		{
+			text:          "I<UTOD231458907<<<<<<<<<<<<<<<\n7408122F1204159UTO<<<<<<<<<<<6",
+			expectedError: document.ErrInvalidMRZ,
+		},
+		{
+			text:          "I<UTOD231458907<<<<<<<<<<<<<<\n7408122F1204159UTO<<<<<<<<<<<6\nERIKSSON<<ANNA<MARIA<<<<<<<<<<",
+			expectedError: document.ErrInvalidMRZ,
+		},
+		{
+			text:          "I<UTOD231458907<<<<<<<<<<<<<<<\n7408122F1204159UTO<<<<<<<<<<<6\nERIKSSON<<ANNA-MARIA<<<<<<<<<<",
+			expectedError: document.ErrInvalidMRZ,
+		},
+	}
+
+	for _, testCase := range testCases {
+		mrz, err := document.ParseMRZ(testCase.text)
+		if !errors.Is(err, testCase.expectedError) {
+			t.Errorf("Expected error %v but got %v", testCase.expectedError, err)
+			continue
+		}
+
+		if err == nil {
+			err = mrz.Validate()
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}
+	}
+}
+
+func Test_MRZValidateLength(t *testing.T) {
+	testCases := []document.MRZ{
+		{},
+		{"IDSRB", "8705231M3103142SRB<<<<<<<<<<<4", "DURDEVIC<<LJUBISA<<<<<<<<<<<<<"},
+	}
+
+	for _, mrz := range testCases {
+		err := mrz.Validate()
+		if !errors.Is(err, document.ErrInvalidMRZ) {
+			t.Errorf("Expected error %v but got %v", document.ErrInvalidMRZ, err)
+		}
+	}
+}
+
+func Test_CompareMRZ(t *testing.T) {
+	testCases := []struct {
+		mrz      document.MRZ
+		expected []string
+	}{
+		{
+			mrz:      documentIdMRZ.MRZ(),
+			expected: []string{},
+		},
+		{
+			mrz: document.MRZ{
+				"IDSRB01234567942305987711232<<",
+				"8705231M3103142SRB<<<<<<<<<<<4",
+				"DURDEVIC<COLIC<<LJUBISA<ZARKO<",
+			},
+			expected: []string{"CheckDigits", "DocRegNo"},
+		},
+		{
+			mrz: document.MRZ{
+				"IDSRB01234567842305987711232<<",
+				"8705231F3103142SRB<<<<<<<<<<<4",
+				"DURDEVIC<<LJUBISA<<<<<<<<<<<<<",
+			},
+			expected: []string{"Sex", "Surname", "GivenName"},
+		},
+		{
+			mrz:      document.MRZ{},
+			expected: []string{"CheckDigits", "DocumentType", "DocRegNo", "PersonalNumber", "DateOfBirth", "Sex", "ExpiryDate", "Surname", "GivenName"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		mismatches := documentIdMRZ.CompareMRZ(testCase.mrz)
+		if !slices.Equal(mismatches, testCase.expected) {
+			t.Errorf("Expected %v but got %v", testCase.expected, mismatches)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package document
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Unit of the quantity. Counts don't have a unit.
+type Unit string
+
+const (
+	UnitNone                Unit = ""
+	UnitCubicCentimetre     Unit = "cm³"
+	UnitKilowatt            Unit = "kW"
+	UnitHorsepower          Unit = "hp" // Metric horsepower
+	UnitKilogram            Unit = "kg"
+	UnitKilowattPerKilogram Unit = "kW/kg"
+)
+
+// Number of metric horsepower in one kilowatt.
+const horsepowerPerKilowatt = 1 / 0.73549875
+
+// Numeric value with the unit, parsed from the text read from the card.
+// Valid is false if the value is not available or it can't be parsed.
+// In JSON, quantities are encoded as numbers (or as null if they are not valid).
+type Quantity struct {
+	Value float64
+	Unit  Unit
+	Valid bool
+}
+
+// Parses the number from the text. Both decimal point and decimal comma are accepted.
+func ParseQuantity(text string, unit Unit) Quantity {
+	text = strings.ReplaceAll(strings.TrimSpace(text), ",", ".")
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return Quantity{Unit: unit}
+	}
+
+	return Quantity{Value: value, Unit: unit, Valid: true}
+}
+
+// Returns the value with the unit, e.g. "1598 cm³", or an empty string if the quantity is not valid.
+func (q Quantity) String() string {
+	if !q.Valid {
+		return ""
+	}
+
+	value := strconv.FormatFloat(q.Value, 'f', -1, 64)
+	if q.Unit == UnitNone {
+		return value
+	}
+
+	return value + " " + string(q.Unit)
+}
+
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	if !q.Valid {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(q.Value)
+}
+
+// Engine capacity in cm³.
+func (doc *VehicleDocument) GetEngineCapacity() Quantity {
+	return ParseQuantity(doc.EngineCapacity, UnitCubicCentimetre)
+}
+
+// Maximum net power in kW.
+func (doc *VehicleDocument) GetMaximumNetPower() Quantity {
+	return ParseQuantity(doc.MaximumNetPower, UnitKilowatt)
+}
+
+// Maximum net power in metric horsepower, rounded to the whole number.
+func (doc *VehicleDocument) GetMaximumNetPowerHp() Quantity {
+	power := doc.GetMaximumNetPower()
+	if !power.Valid {
+		return Quantity{Unit: UnitHorsepower}
+	}
+
+	return Quantity{Value: math.Round(power.Value * horsepowerPerKilowatt), Unit: UnitHorsepower, Valid: true}
+}
+
+// Mass of the vehicle in kg.
+func (doc *VehicleDocument) GetVehicleMass() Quantity {
+	return ParseQuantity(doc.VehicleMass, UnitKilogram)
+}
+
+// Maximum permissible laden mass in kg.
+func (doc *VehicleDocument) GetMaximumPermissibleLadenMass() Quantity {
+	return ParseQuantity(doc.MaximumPermissibleLadenMass, UnitKilogram)
+}
+
+// Load capacity in kg, as written on the card.
+func (doc *VehicleDocument) GetVehicleLoad() Quantity {
+	return ParseQuantity(doc.VehicleLoad, UnitKilogram)
+}
+
+// Payload in kg. The load capacity from the card is used if it is available,
+// and otherwise the payload is the difference between the maximum permissible laden mass and the mass of the vehicle.
+func (doc *VehicleDocument) GetPayload() Quantity {
+	load := doc.GetVehicleLoad()
+	if load.Valid {
+		return load
+	}
+
+	ladenMass := doc.GetMaximumPermissibleLadenMass()
+	mass := doc.GetVehicleMass()
+	if !ladenMass.Valid || !mass.Valid {
+		return Quantity{Unit: UnitKilogram}
+	}
+
+	return Quantity{Value: ladenMass.Value - mass.Value, Unit: UnitKilogram, Valid: true}
+}
+
+// Power-to-weight ratio in kW/kg.
+func (doc *VehicleDocument) GetPowerWeightRatio() Quantity {
+	return ParseQuantity(doc.PowerWeightRatio, UnitKilowattPerKilogram)
+}
+
+func (doc *VehicleDocument) GetNumberOfAxles() Quantity {
+	return ParseQuantity(doc.NumberOfAxles, UnitNone)
+}
+
+func (doc *VehicleDocument) GetNumberOfSeats() Quantity {
+	return ParseQuantity(doc.NumberOfSeats, UnitNone)
+}
+
+func (doc *VehicleDocument) GetNumberOfStandingPlaces() Quantity {
+	return ParseQuantity(doc.NumberOfStandingPlaces, UnitNone)
+}
+
+// Numeric attributes of the vehicle, as they are written to JSON and Excel.
+// Names end with the unit, so the values can be used without the unit.
+type VehicleQuantities struct {
+	EngineCapacityCm3             Quantity
+	MaximumNetPowerKW             Quantity
+	MaximumNetPowerHp             Quantity
+	VehicleMassKg                 Quantity
+	MaximumPermissibleLadenMassKg Quantity
+	VehicleLoadKg                 Quantity
+	PayloadKg                     Quantity
+	PowerWeightRatioKWKg          Quantity
+	NumberOfAxles                 Quantity
+	NumberOfSeats                 Quantity
+	NumberOfStandingPlaces        Quantity
+}
+
+func (doc *VehicleDocument) GetQuantities() VehicleQuantities {
+	return VehicleQuantities{
+		EngineCapacityCm3:             doc.GetEngineCapacity(),
+		MaximumNetPowerKW:             doc.GetMaximumNetPower(),
+		MaximumNetPowerHp:             doc.GetMaximumNetPowerHp(),
+		VehicleMassKg:                 doc.GetVehicleMass(),
+		MaximumPermissibleLadenMassKg: doc.GetMaximumPermissibleLadenMass(),
+		VehicleLoadKg:                 doc.GetVehicleLoad(),
+		PayloadKg:                     doc.GetPayload(),
+		PowerWeightRatioKWKg:          doc.GetPowerWeightRatio(),
+		NumberOfAxles:                 doc.GetNumberOfAxles(),
+		NumberOfSeats:                 doc.GetNumberOfSeats(),
+		NumberOfStandingPlaces:        doc.GetNumberOfStandingPlaces(),
+	}
+}
+
+// Appends quantities to the Excel file as numeric cells. Quantities that are not valid are left empty.
+func appendQuantities(f *excelize.File, quantities VehicleQuantities) error {
+	value := reflect.ValueOf(quantities)
+	for i := range value.NumField() {
+		quantity := value.Field(i).Interface().(Quantity)
+
+		var cell any = ""
+		if quantity.Valid {
+			cell = quantity.Value
+		}
+
+		err := appendExcelRow(f, value.Type().Field(i).Name, cell)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
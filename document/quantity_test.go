@@ -0,0 +1,127 @@
+package document_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ubavic/bas-celik/document"
+	"github.com/xuri/excelize/v2"
+)
+
+var documentVehicleQuantities = document.VehicleDocument{
+	EngineCapacity:              "1598.00",
+	MaximumNetPower:             "77,00",
+	VehicleMass:                 "1295",
+	MaximumPermissibleLadenMass: "1830",
+	PowerWeightRatio:            "0.06",
+	NumberOfAxles:               "2",
+	NumberOfSeats:               "5",
+	NumberOfStandingPlaces:      "",
+}
+
+func Test_ParseQuantity(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected document.Quantity
+	}{
+		{text: "1598.00", expected: document.Quantity{Value: 1598, Unit: document.UnitCubicCentimetre, Valid: true}},
+		{text: " 0,06 ", expected: document.Quantity{Value: 0.06, Unit: document.UnitCubicCentimetre, Valid: true}},
+		{text: "", expected: document.Quantity{Unit: document.UnitCubicCentimetre}},
+		{text: "NaN", expected: document.Quantity{Unit: document.UnitCubicCentimetre}},
+		{text: "12 cm", expected: document.Quantity{Unit: document.UnitCubicCentimetre}},
+	}
+
+	for _, testCase := range testCases {
+		quantity := document.ParseQuantity(testCase.text, document.UnitCubicCentimetre)
+		if quantity != testCase.expected {
+			t.Errorf("Expected %v for '%s' but got %v", testCase.expected, testCase.text, quantity)
+		}
+	}
+}
+
+func Test_VehicleQuantities(t *testing.T) {
+	testCases := []struct {
+		quantity document.Quantity
+		expected string
+	}{
+		{quantity: documentVehicleQuantities.GetEngineCapacity(), expected: "1598 cm³"},
+		{quantity: documentVehicleQuantities.GetMaximumNetPower(), expected: "77 kW"},
+		{quantity: documentVehicleQuantities.GetMaximumNetPowerHp(), expected: "105 hp"},
+		{quantity: documentVehicleQuantities.GetPayload(), expected: "535 kg"},
+		{quantity: documentVehicleQuantities.GetNumberOfSeats(), expected: "5"},
+		{quantity: documentVehicleQuantities.GetNumberOfStandingPlaces(), expected: ""},
+		{quantity: documentVehicle1.GetPayload(), expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		if testCase.quantity.String() != testCase.expected {
+			t.Errorf("Expected '%s' but got '%s'", testCase.expected, testCase.quantity)
+		}
+	}
+}
+
+func Test_VehicleQuantitiesJson(t *testing.T) {
+	data, err := documentVehicleQuantities.BuildJson()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	var decoded struct {
+		Quantities map[string]*float64
+	}
+
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	if value := decoded.Quantities["EngineCapacityCm3"]; value == nil || *value != 1598 {
+		t.Errorf("Expected engine capacity 1598 but got %v", value)
+	}
+
+	if value := decoded.Quantities["NumberOfStandingPlaces"]; value != nil {
+		t.Errorf("Expected null but got %v", *value)
+	}
+}
+
+func Test_VehicleQuantitiesExcel(t *testing.T) {
+	data, _, err := documentVehicleQuantities.BuildExcel()
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	rows, err := f.GetRows("Sheet1")
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+
+	for i, row := range rows {
+		if row[0] != "MaximumNetPowerKW" {
+			continue
+		}
+
+		cellType, err := f.GetCellType("Sheet1", fmt.Sprintf("B%d", i+1))
+		if err != nil {
+			t.Fatalf("Unexpected error %v", err)
+		}
+
+		if cellType != excelize.CellTypeNumber && cellType != excelize.CellTypeUnset {
+			t.Errorf("Expected number cell, but got type %v", cellType)
+		}
+
+		if len(row) < 2 || row[1] != "77" {
+			t.Errorf("Expected 77, but got %v", row)
+		}
+
+		return
+	}
+
+	t.Errorf("Quantity row not found")
+}
@@ -0,0 +1,303 @@
+package document
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/signintech/gopdf"
+	"github.com/ubavic/bas-celik/localization"
+)
+
+const TACHO_ACTIVITY_REST = "break/rest"
+const TACHO_ACTIVITY_AVAILABILITY = "availability"
+const TACHO_ACTIVITY_WORK = "work"
+const TACHO_ACTIVITY_DRIVING = "driving"
+
+// Represents a document stored on an EU digital tachograph driver card.
+type TachographDocument struct {
+	CardIssuingMemberState         string
+	CardNumber                     string
+	CardIssuingAuthorityName       string
+	CardIssueDate                  Date
+	CardValidityBegin              Date
+	CardExpiryDate                 Date
+	HolderSurname                  string
+	HolderFirstNames               string
+	DateOfBirth                    Date
+	PreferredLanguage              string
+	DrivingLicenceIssuingAuthority string
+	DrivingLicenceIssuingNation    string
+	DrivingLicenceNumber           string
+	ActivityDays                   []TachographActivityDay
+}
+
+// Represents activities of the driver recorded during a single day.
+type TachographActivityDay struct {
+	Date            Date
+	PresenceCounter string
+	Distance        uint // Distance travelled in kilometers
+	Activities      []TachographActivity
+}
+
+// Represents a single activity of the driver.
+// An activity lasts until the start of the next activity, or until the end of the day.
+type TachographActivity struct {
+	Activity     string
+	StartMinute  uint // Minutes since 00:00 UTC
+	Duration     uint // Duration in minutes
+	CoDriver     bool
+	Crew         bool
+	CardInserted bool
+}
+
+func (doc *TachographDocument) GetFullName() string {
+	return localization.JoinWithComma(doc.HolderFirstNames, doc.HolderSurname)
+}
+
+// Computes durations of activities from their start times.
+func (day *TachographActivityDay) SetDurations() {
+	for i := range day.Activities {
+		end := uint(24 * 60)
+		if i+1 < len(day.Activities) {
+			end = day.Activities[i+1].StartMinute
+		}
+
+		if end > day.Activities[i].StartMinute {
+			day.Activities[i].Duration = end - day.Activities[i].StartMinute
+		} else {
+			day.Activities[i].Duration = 0
+		}
+	}
+}
+
+// Returns total duration (in minutes) of the given activity during the day.
+func (day *TachographActivityDay) Total(activity string) uint {
+	total := uint(0)
+	for _, a := range day.Activities {
+		if a.Activity == activity {
+			total += a.Duration
+		}
+	}
+
+	return total
+}
+
+// Returns start time of the activity in HH:MM format.
+func (activity *TachographActivity) Start() string {
+	return fmt.Sprintf("%02d:%02d", activity.StartMinute/60, activity.StartMinute%60)
+}
+
+func (doc *TachographDocument) BuildPdf() (data []byte, fileName string, retErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch x := r.(type) {
+			case error:
+				retErr = x
+			default:
+				retErr = errors.New("unknown panic")
+			}
+		}
+	}()
+
+	pdf := gopdf.GoPdf{}
+	pdf.Start(gopdf.Config{PageSize: *gopdf.PageSizeA4})
+
+	pdf.AddPage()
+
+	err := pdf.AddTTFFontData("liberationsans", fontRegular)
+	if err != nil {
+		panic(fmt.Errorf("loading font: %w", err))
+	}
+
+	err = pdf.AddTTFFontDataWithOption("liberationsans", fontBold, gopdf.TtfOption{Style: gopdf.Bold})
+	if err != nil {
+		panic(fmt.Errorf("loading font: %w", err))
+	}
+
+	const leftMargin = 28
+	const rightMargin = 535
+	const textLeftMargin = 38
+	const pageBottom = 800
+
+	cell := func(s string) {
+		err := pdf.Cell(nil, s)
+		if err != nil {
+			panic(fmt.Errorf("putting text: %w", err))
+		}
+	}
+
+	setFont := func(style string, size float64) {
+		err := pdf.SetFont("liberationsans", style, size)
+		if err != nil {
+			panic(fmt.Errorf("setting font: %w", err))
+		}
+	}
+
+	newLine := func(height float64) {
+		if pdf.GetY()+height > pageBottom {
+			pdf.AddPage()
+			pdf.SetXY(textLeftMargin, 40)
+			return
+		}
+		pdf.SetXY(textLeftMargin, pdf.GetY()+height)
+	}
+
+	putData := func(label, data string) {
+		cell(label + ": " + data)
+		newLine(18)
+	}
+
+	section := func(name string) {
+		newLine(6)
+		pdf.Line(leftMargin, pdf.GetY(), rightMargin, pdf.GetY())
+		newLine(8)
+		setFont("B", 12)
+		cell(name)
+		setFont("", 11)
+		newLine(22)
+	}
+
+	setFont("B", 22)
+	pdf.SetXY(textLeftMargin, 35)
+	cell("Kartica vozača za digitalni tahograf")
+
+	pdf.SetLineWidth(1.5)
+	pdf.SetLineType("solid")
+	pdf.Line(leftMargin, 68, rightMargin, 68)
+
+	pdf.SetXY(textLeftMargin, 80)
+	setFont("", 11)
+	pdf.SetLineWidth(0.5)
+
+	section("Podaci o vozaču")
+	putData("Prezime", doc.HolderSurname)
+	putData("Ime", doc.HolderFirstNames)
+	putData("Datum rođenja", doc.DateOfBirth.Text)
+	putData("Jezik", doc.PreferredLanguage)
+
+	section("Podaci o kartici")
+	putData("Broj kartice", doc.CardNumber)
+	putData("Država izdavanja", doc.CardIssuingMemberState)
+	putData("Karticu izdao", doc.CardIssuingAuthorityName)
+	putData("Datum izdavanja", doc.CardIssueDate.Text)
+	putData("Važi od", doc.CardValidityBegin.Text)
+	putData("Važi do", doc.CardExpiryDate.Text)
+
+	section("Podaci o vozačkoj dozvoli")
+	putData("Broj vozačke dozvole", doc.DrivingLicenceNumber)
+	putData("Država izdavanja", doc.DrivingLicenceIssuingNation)
+	putData("Dozvolu izdao", doc.DrivingLicenceIssuingAuthority)
+
+	section("Aktivnosti (minuti)")
+	columns := []float64{textLeftMargin, 140, 220, 300, 380, 460}
+	row := func(values ...string) {
+		for i, value := range values {
+			pdf.SetX(columns[i])
+			cell(value)
+		}
+		newLine(16)
+	}
+
+	row("Datum", "Km", "Vožnja", "Rad", "Raspoloživost", "Odmor")
+	for _, day := range doc.ActivityDays {
+		row(
+			day.Date.Text,
+			fmt.Sprint(day.Distance),
+			fmt.Sprint(day.Total(TACHO_ACTIVITY_DRIVING)),
+			fmt.Sprint(day.Total(TACHO_ACTIVITY_WORK)),
+			fmt.Sprint(day.Total(TACHO_ACTIVITY_AVAILABILITY)),
+			fmt.Sprint(day.Total(TACHO_ACTIVITY_REST)),
+		)
+	}
+
+	fileName = doc.formatFilename() + ".pdf"
+
+	pdf.SetInfo(gopdf.PdfInfo{
+		Title:        doc.HolderFirstNames + " " + doc.HolderSurname,
+		Author:       "Baš Čelik",
+		Subject:      "Kartica vozača",
+		CreationDate: time.Now(),
+	})
+
+	return pdf.GetBytesPdf(), fileName, nil
+}
+
+func (doc *TachographDocument) BuildJson() ([]byte, error) {
+	type Alias TachographDocument
+	return json.Marshal(&struct {
+		*Alias
+		Validity Validity
+	}{
+		Alias:    (*Alias)(doc),
+		Validity: doc.ValidityAt(time.Now()),
+	})
+}
+
+// Builds an Excel file with identification data in the first sheet,
+// daily summaries in the second and all recorded activities in the third sheet.
+func (doc *TachographDocument) BuildExcel() ([]byte, string, error) {
+	fileName := doc.formatFilename() + ".xlsx"
+
+	f, err := createExcelFile(*doc)
+	if err != nil {
+		return nil, fileName, err
+	}
+
+	const daysSheet = "Days"
+	const activitiesSheet = "Activities"
+
+	_, err = f.NewSheet(daysSheet)
+	if err != nil {
+		return nil, fileName, err
+	}
+
+	_, err = f.NewSheet(activitiesSheet)
+	if err != nil {
+		return nil, fileName, err
+	}
+
+	putRow := func(sheet string, row int, values ...any) {
+		for i, value := range values {
+			setCell(f, sheet, fmt.Sprintf("%c%d", 'A'+i, row), value)
+		}
+	}
+
+	putRow(daysSheet, 1, "Date", "PresenceCounter", "Distance", "Driving", "Work", "Availability", "Rest")
+	putRow(activitiesSheet, 1, "Date", "Start", "Duration", "Activity", "CoDriver", "Crew", "CardInserted")
+
+	activityRow := 2
+	for i, day := range doc.ActivityDays {
+		putRow(daysSheet, i+2,
+			day.Date,
+			day.PresenceCounter,
+			day.Distance,
+			day.Total(TACHO_ACTIVITY_DRIVING),
+			day.Total(TACHO_ACTIVITY_WORK),
+			day.Total(TACHO_ACTIVITY_AVAILABILITY),
+			day.Total(TACHO_ACTIVITY_REST),
+		)
+
+		for _, activity := range day.Activities {
+			putRow(activitiesSheet, activityRow,
+				day.Date,
+				activity.Start(),
+				activity.Duration,
+				activity.Activity,
+				localization.FormatYesNo(activity.CoDriver, localization.En),
+				localization.FormatYesNo(activity.Crew, localization.En),
+				localization.FormatYesNo(activity.CardInserted, localization.En),
+			)
+			activityRow++
+		}
+	}
+
+	xlsx, err := writeExcelFile(f)
+	return xlsx, fileName, err
+}
+
+func (doc *TachographDocument) formatFilename() string {
+	return strings.ToLower(doc.HolderFirstNames + "_" + doc.HolderSurname)
+}
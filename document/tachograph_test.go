@@ -0,0 +1,82 @@
+package document_test
+
+import (
+	"testing"
+
+	"github.com/ubavic/bas-celik/document"
+)
+
+var documentTachograph1 = document.TachographDocument{}
+var documentTachograph2 = document.TachographDocument{
+	HolderSurname:        "Petrović",
+	HolderFirstNames:     "Petar",
+	CardNumber:           "SRB0000012345600",
+	DrivingLicenceNumber: "123456",
+	ActivityDays: []document.TachographActivityDay{
+		{
+			Date:     document.ParseDateText("14.11.2023."),
+			Distance: 100,
+			Activities: []document.TachographActivity{
+				{Activity: document.TACHO_ACTIVITY_DRIVING, StartMinute: 360, Duration: 240},
+				{Activity: document.TACHO_ACTIVITY_REST, StartMinute: 600, Duration: 840},
+			},
+		},
+	},
+}
+
+func Test_BuildPdfTachograph(t *testing.T) {
+	unsetDocumentConfig()
+
+	_, _, err := documentTachograph1.BuildPdf()
+	if err == nil {
+		t.Errorf("Expected error but got %v", err)
+	}
+
+	setDocumentConfigFromLocalFiles(t)
+
+	_, _, err = documentTachograph1.BuildPdf()
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	_, _, err = documentTachograph2.BuildPdf()
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func Test_BuildExcelTachograph(t *testing.T) {
+	_, fileName, err := documentTachograph2.BuildExcel()
+	if err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+
+	if fileName != "petar_petrović.xlsx" {
+		t.Errorf("Unexpected file name %s", fileName)
+	}
+}
+
+func Test_SetDurations(t *testing.T) {
+	day := document.TachographActivityDay{
+		Activities: []document.TachographActivity{
+			{Activity: document.TACHO_ACTIVITY_REST, StartMinute: 0},
+			{Activity: document.TACHO_ACTIVITY_DRIVING, StartMinute: 480},
+			{Activity: document.TACHO_ACTIVITY_WORK, StartMinute: 600},
+			{Activity: document.TACHO_ACTIVITY_REST, StartMinute: 1020},
+		},
+	}
+
+	day.SetDurations()
+
+	if day.Total(document.TACHO_ACTIVITY_REST) != 900 {
+		t.Errorf("Expected 900 minutes of rest, but got %d", day.Total(document.TACHO_ACTIVITY_REST))
+	}
+
+	if day.Activities[2].Duration != 420 {
+		t.Errorf("Expected 420 minutes of work, but got %d", day.Activities[2].Duration)
+	}
+
+	if day.Activities[1].Start() != "08:00" {
+		t.Errorf("Expected start at 08:00, but got %s", day.Activities[1].Start())
+	}
+}
@@ -0,0 +1,81 @@
+package document
+
+import (
+	"time"
+)
+
+// Number of days before the expiry date in which the document is considered to expire soon.
+const ExpiresSoonDays = 30
+
+// Describes whether the document is valid.
+type ValidityStatus string
+
+const (
+	ValidityUnknown ValidityStatus = "unknown" // Expiry date is not available
+	Valid           ValidityStatus = "valid"
+	ExpiresSoon     ValidityStatus = "expiresSoon" // Valid, but expires in less than ExpiresSoonDays days
+	Expired         ValidityStatus = "expired"
+)
+
+// Represents validity of the document on some date.
+type Validity struct {
+	Status        ValidityStatus
+	ExpiryDate    Date // Last day on which the document is valid
+	DaysRemaining int  // Number of days until the expiry date. Negative if the document is expired
+	Permanent     bool // Document is valid regardless of the expiry date
+}
+
+// Implemented by documents that have an expiry date.
+type ValidityChecker interface {
+	ValidityAt(t time.Time) Validity
+}
+
+// Evaluates validity on the day of t. The document is valid on its expiry date.
+func NewValidity(expiryDate Date, t time.Time) Validity {
+	validity := Validity{ExpiryDate: expiryDate}
+	if expiryDate.IsZero() {
+		validity.Status = ValidityUnknown
+		return validity
+	}
+
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	expiry := time.Date(expiryDate.Time.Year(), expiryDate.Time.Month(), expiryDate.Time.Day(), 0, 0, 0, 0, time.UTC)
+	validity.DaysRemaining = int(expiry.Sub(day).Hours() / 24)
+
+	switch {
+	case validity.DaysRemaining < 0:
+		validity.Status = Expired
+	case validity.DaysRemaining < ExpiresSoonDays:
+		validity.Status = ExpiresSoon
+	default:
+		validity.Status = Valid
+	}
+
+	return validity
+}
+
+// Checks if the document is valid (including the documents that expire soon).
+func (validity Validity) IsValid() bool {
+	return validity.Status == Valid || validity.Status == ExpiresSoon
+}
+
+func (doc *IdDocument) ValidityAt(t time.Time) Validity {
+	return NewValidity(doc.ExpiryDate, t)
+}
+
+func (doc *VehicleDocument) ValidityAt(t time.Time) Validity {
+	return NewValidity(doc.ExpiryDate, t)
+}
+
+// Validity of the medical document is determined by the date until which the insurance is verified.
+func (doc *MedicalDocument) ValidityAt(t time.Time) Validity {
+	if doc.PermanentlyValid {
+		return Validity{Status: Valid, ExpiryDate: doc.ValidUntil, Permanent: true}
+	}
+
+	return NewValidity(doc.ValidUntil, t)
+}
+
+func (doc *TachographDocument) ValidityAt(t time.Time) Validity {
+	return NewValidity(doc.CardExpiryDate, t)
+}
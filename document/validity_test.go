@@ -0,0 +1,69 @@
+package document_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ubavic/bas-celik/document"
+)
+
+func Test_Validity(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 30, 0, 0, time.Local)
+
+	testCases := []struct {
+		name           string
+		doc            document.ValidityChecker
+		expectedStatus document.ValidityStatus
+		expectedDays   int
+	}{
+		{
+			name:           "valid id",
+			doc:            &document.IdDocument{ExpiryDate: document.ParseDateDMY("10042024")},
+			expectedStatus: document.Valid,
+			expectedDays:   31,
+		},
+		{
+			name:           "id expires soon",
+			doc:            &document.IdDocument{ExpiryDate: document.ParseDateDMY("08042024")},
+			expectedStatus: document.ExpiresSoon,
+			expectedDays:   29,
+		},
+		{
+			name:           "last valid day",
+			doc:            &document.VehicleDocument{ExpiryDate: document.ParseDateYMD("20240310")},
+			expectedStatus: document.ExpiresSoon,
+			expectedDays:   0,
+		},
+		{
+			name:           "expired vehicle",
+			doc:            &document.VehicleDocument{ExpiryDate: document.ParseDateYMD("20240309")},
+			expectedStatus: document.Expired,
+			expectedDays:   -1,
+		},
+		{
+			name:           "lapsed insurance",
+			doc:            &document.MedicalDocument{ValidUntil: document.ParseDateText("1.1.2024.")},
+			expectedStatus: document.Expired,
+			expectedDays:   -69,
+		},
+		{
+			name:           "permanently valid insurance",
+			doc:            &document.MedicalDocument{ValidUntil: document.ParseDateText("1.1.2024."), PermanentlyValid: true},
+			expectedStatus: document.Valid,
+		},
+		{
+			name:           "unknown expiry date",
+			doc:            &document.IdDocument{ExpiryDate: document.ParseDateDMY("01010001")},
+			expectedStatus: document.ValidityUnknown,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			validity := testCase.doc.ValidityAt(now)
+			if validity.Status != testCase.expectedStatus || validity.DaysRemaining != testCase.expectedDays {
+				t.Errorf("Expected %s with %d days remaining, but got %s with %d days", testCase.expectedStatus, testCase.expectedDays, validity.Status, validity.DaysRemaining)
+			}
+		})
+	}
+}
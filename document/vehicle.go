@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,45 +13,54 @@ import (
 
 // Represents a document stored on a Serbian vehicle card.
 // Fields are named according to official API.
+// Struct tags describe where fields are stored on the card (see the mapper package).
+// Paths of EngineRatedSpeed and HomologationMark are not known, so the fields stay empty
+// and their values are kept in Extra.
+// User fields (except UsersPersonalNo) are assigned by the card package, from a single file.
 type VehicleDocument struct {
-	AuthorityIssuing            string
-	ColourOfVehicle             string
-	CommercialDescription       string
-	CompetentAuthority          string
-	DateOfFirstRegistration     string
-	EngineCapacity              string
-	EngineIdNumber              string
+	AuthorityIssuing            string `ber:"71.9F36"`
+	ColourOfVehicle             string `ber:"72.9F24"`
+	CommercialDescription       string `ber:"71.A3.89"`
+	CompetentAuthority          string `ber:"71.9F35"`
+	DateOfFirstRegistration     Date   `ber:"71.82" date:"ymd"`
+	EngineCapacity              string `ber:"71.A5.90"`
+	EngineIdNumber              string `ber:"72.A5.9E"`
 	EngineRatedSpeed            string
-	ExpiryDate                  string
+	ExpiryDate                  Date `ber:"71.8D" date:"ymd"`
 	HomologationMark            string
-	IssuingDate                 string
-	MaximumNetPower             string
-	MaximumPermissibleLadenMass string
-	NumberOfAxles               string
-	NumberOfSeats               string
-	NumberOfStandingPlaces      string
-	OwnerAddress                string
-	OwnerName                   string
-	OwnersPersonalNo            string
-	OwnersSurnameOrBusinessName string
-	PowerWeightRatio            string
-	RegistrationNumberOfVehicle string
-	SerialNumber                string
-	StateIssuing                string
-	TypeApprovalNumber          string
-	TypeOfFuel                  string
-	UnambiguousNumber           string
+	IssuingDate                 Date   `ber:"71.8E" date:"ymd"`
+	MaximumNetPower             string `ber:"71.A5.91"`
+	MaximumPermissibleLadenMass string `ber:"71.A4.8B"`
+	NumberOfAxles               string `ber:"72.99"`
+	NumberOfSeats               string `ber:"71.A6.94"`
+	NumberOfStandingPlaces      string `ber:"71.A6.95"`
+	OwnerAddress                string `ber:"71.A1.A2.85"`
+	OwnerName                   string `ber:"71.A1.A2.84"`
+	OwnersPersonalNo            string `ber:"72.C2"`
+	OwnersSurnameOrBusinessName string `ber:"71.A1.A2.83"`
+	PowerWeightRatio            string `ber:"71.93"`
+	RegistrationNumberOfVehicle string `ber:"71.81"`
+	SerialNumber                string `ber:"72.C9"`
+	StateIssuing                string `ber:"71.9F33"`
+	TypeApprovalNumber          string `ber:"71.8F"`
+	TypeOfFuel                  string `ber:"71.A5.92"`
+	UnambiguousNumber           string `ber:"71.9F38"`
 	UsersAddress                string
 	UsersName                   string
-	UsersPersonalNo             string
+	UsersPersonalNo             string `ber:"72.C3"`
 	UsersSurnameOrBusinessName  string
-	VehicleCategory             string
-	VehicleIdNumber             string
-	VehicleLoad                 string
-	VehicleMake                 string
-	VehicleMass                 string
-	VehicleType                 string
-	YearOfProduction            string
+	VehicleCategory             string `ber:"72.98"`
+	VehicleIdNumber             string `ber:"71.8A"`
+	VehicleLoad                 string `ber:"72.C4"`
+	VehicleMake                 string `ber:"71.A3.87"`
+	VehicleMass                 string `ber:"71.8C"`
+	VehicleType                 string `ber:"71.A3.88"`
+	YearOfProduction            string `ber:"72.C5"`
+	Extra                       Extra  `ber:"*" json:",omitempty"`
+}
+
+func (doc *VehicleDocument) GetExtra() Extra {
+	return doc.Extra
 }
 
 func (doc *VehicleDocument) BuildPdf() (data []byte, fileName string, retErr error) {
@@ -178,9 +188,9 @@ func (doc *VehicleDocument) BuildPdf() (data []byte, fileName string, retErr err
 		panic(fmt.Errorf("setting font: %w", err))
 	}
 
-	putData("Datum izdavanja", doc.IssuingDate)
+	putData("Datum izdavanja", doc.IssuingDate.Text)
 	tab()
-	putUnderline("Važi do: "+doc.ExpiryDate, 12)
+	putUnderline("Važi do: "+doc.ExpiryDate.Text, 12)
 	newLine()
 
 	putData("Saobraćajnu izdao", doc.StateIssuing)
@@ -227,7 +237,7 @@ func (doc *VehicleDocument) BuildPdf() (data []byte, fileName string, retErr err
 	putUnderline("Podaci o vozilu", 20)
 	pdf.SetXY(textLeftMargin, pdf.GetY()+25)
 
-	putData("Datum prve registracije", doc.DateOfFirstRegistration)
+	putData("Datum prve registracije", doc.DateOfFirstRegistration.Text)
 	tab()
 	putData("Godina proizvodnje", doc.YearOfProduction)
 	newLine()
@@ -280,6 +290,10 @@ func (doc *VehicleDocument) BuildPdf() (data []byte, fileName string, retErr err
 	putData("Broj mesta za stajanje", doc.NumberOfStandingPlaces)
 	newLine()
 
+	if len(doc.VehicleIdNumber) > 0 {
+		putParagraph("Provera broja šasije: " + formatVINCheck(doc.CheckVIN()))
+	}
+
 	fileName = doc.formatFilename() + ".pdf"
 
 	pdf.SetInfo(gopdf.PdfInfo{
@@ -293,15 +307,99 @@ func (doc *VehicleDocument) BuildPdf() (data []byte, fileName string, retErr err
 }
 
 func (doc *VehicleDocument) BuildJson() ([]byte, error) {
-	return json.Marshal(doc)
+	var vinCheck *VINCheck
+	if len(doc.VehicleIdNumber) > 0 {
+		check := doc.CheckVIN()
+		vinCheck = &check
+	}
+
+	type Alias VehicleDocument
+	return json.Marshal(&struct {
+		*Alias
+		Validity   Validity
+		JMBG       []JMBGCheck `json:",omitempty"`
+		VIN        *VINCheck   `json:",omitempty"`
+		Quantities VehicleQuantities
+	}{
+		Alias:      (*Alias)(doc),
+		Validity:   doc.ValidityAt(time.Now()),
+		JMBG:       doc.CheckJMBG(),
+		VIN:        vinCheck,
+		Quantities: doc.GetQuantities(),
+	})
 }
 
 func (doc *VehicleDocument) BuildExcel() ([]byte, string, error) {
-	xlsx, err := CreateExcel(*doc)
 	fileName := doc.formatFilename() + ".xlsx"
+
+	f, err := createExcelFile(*doc)
+	if err != nil {
+		return nil, fileName, err
+	}
+
+	err = appendQuantities(f, doc.GetQuantities())
+	if err != nil {
+		return nil, fileName, fmt.Errorf("adding quantities: %w", err)
+	}
+
+	if len(doc.VehicleIdNumber) > 0 {
+		check := doc.CheckVIN()
+		rows := []struct {
+			label string
+			value any
+		}{
+			{"VINError", check.Error},
+			{"VINMismatches", strings.Join(check.Mismatches, ", ")},
+			{"VINManufacturer", check.Manufacturer},
+			{"VINModelYear", ""},
+		}
+
+		if check.ModelYear > 0 {
+			rows[3].value = check.ModelYear
+		}
+
+		for _, row := range rows {
+			err = appendExcelRow(f, row.label, row.value)
+			if err != nil {
+				return nil, fileName, fmt.Errorf("adding VIN check: %w", err)
+			}
+		}
+	}
+
+	xlsx, err := writeExcelFile(f)
 	return xlsx, fileName, err
 }
 
+// Describes the result of the VIN check for the PDF, e.g. "ispravan (Volkswagen, 2015)" or "ne slaže se: marka".
+func formatVINCheck(check VINCheck) string {
+	if len(check.Error) > 0 {
+		return "nije ispravan (" + check.Error + ")"
+	}
+
+	if len(check.Mismatches) > 0 {
+		names := map[string]string{"VehicleMake": "marka", "YearOfProduction": "godina proizvodnje"}
+		fields := make([]string, len(check.Mismatches))
+		for i, field := range check.Mismatches {
+			fields[i] = names[field]
+		}
+		return "ne slaže se: " + strings.Join(fields, ", ")
+	}
+
+	decoded := []string{}
+	if len(check.Manufacturer) > 0 {
+		decoded = append(decoded, check.Manufacturer)
+	}
+	if check.ModelYear > 0 {
+		decoded = append(decoded, strconv.Itoa(check.ModelYear))
+	}
+
+	if len(decoded) == 0 {
+		return "ispravan"
+	}
+
+	return "ispravan (" + strings.Join(decoded, ", ") + ")"
+}
+
 func (doc *VehicleDocument) formatFilename() string {
 	return strings.ToLower(doc.RegistrationNumberOfVehicle + "_" + doc.OwnersSurnameOrBusinessName + "_" + doc.OwnerName)
 }
@@ -0,0 +1,281 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Represents the vehicle identification number (ISO 3779): world manufacturer identifier (WMI, positions 1-3),
+// vehicle descriptor section (positions 4-9) and vehicle identifier section (positions 10-17).
+type VIN string
+
+var ErrInvalidVIN = errors.New("invalid VIN")
+
+// Characters that can be used in VIN. Letters I, O and Q are not used.
+const vinCharacters = "0123456789ABCDEFGHJKLMNPRSTUVWXYZ"
+
+// Characters that encode the model year in the 10th position, starting with 1980 and repeating every 30 years.
+const vinModelYearCharacters = "ABCDEFGHJKLMNPRSTVWXY123456789"
+
+// Weights of positions used in the check digit computation.
+var vinWeights = [17]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// Makes of vehicles by the most common WMIs.
+var vinManufacturers = map[string][]string{
+	"JF1": {"Subaru"},
+	"JHM": {"Honda"},
+	"JMB": {"Mitsubishi"},
+	"JMZ": {"Mazda"},
+	"JN1": {"Nissan"},
+	"JSA": {"Suzuki"},
+	"JTD": {"Toyota"},
+	"JTM": {"Toyota"},
+	"KMH": {"Hyundai"},
+	"KNA": {"Kia"},
+	"KNE": {"Kia"},
+	"LRW": {"Tesla"},
+	"MAL": {"Hyundai"},
+	"NMT": {"Toyota"},
+	"SAJ": {"Jaguar"},
+	"SAL": {"Land Rover"},
+	"SB1": {"Toyota"},
+	"SJN": {"Nissan"},
+	"TMA": {"Hyundai"},
+	"TMB": {"Škoda"},
+	"TSM": {"Suzuki"},
+	"U5Y": {"Kia"},
+	"UU1": {"Dacia"},
+	"VF1": {"Renault"},
+	"VF3": {"Peugeot"},
+	"VF7": {"Citroen"},
+	"VNK": {"Toyota"},
+	"VR3": {"Peugeot"},
+	"VR7": {"Citroen"},
+	"VS6": {"Ford"},
+	"VSK": {"Nissan"},
+	"VSS": {"SEAT", "Cupra"},
+	"VX1": {"Zastava"},
+	"W0L": {"Opel", "Vauxhall"},
+	"W0V": {"Opel", "Vauxhall"},
+	"W1K": {"Mercedes-Benz"},
+	"W1N": {"Mercedes-Benz"},
+	"W1V": {"Mercedes-Benz"},
+	"WAU": {"Audi"},
+	"WBA": {"BMW"},
+	"WBS": {"BMW"},
+	"WBY": {"BMW"},
+	"WDB": {"Mercedes-Benz"},
+	"WDC": {"Mercedes-Benz"},
+	"WDD": {"Mercedes-Benz"},
+	"WF0": {"Ford"},
+	"WMA": {"MAN"},
+	"WMW": {"MINI"},
+	"WP0": {"Porsche"},
+	"WP1": {"Porsche"},
+	"WUA": {"Audi"},
+	"WV1": {"Volkswagen"},
+	"WV2": {"Volkswagen"},
+	"WVG": {"Volkswagen"},
+	"WVW": {"Volkswagen"},
+	"XLR": {"DAF"},
+	"XTA": {"Lada"},
+	"YS2": {"Scania"},
+	"YV1": {"Volvo"},
+	"ZAR": {"Alfa Romeo"},
+	"ZCF": {"Iveco"},
+	"ZFA": {"Fiat"},
+	"ZFF": {"Ferrari"},
+	"ZLA": {"Lancia"},
+	"1FA": {"Ford"},
+	"1G1": {"Chevrolet"},
+	"5YJ": {"Tesla"},
+}
+
+// WMIs of manufacturers that encode the model year in the 10th character
+// even when the check digit is not mandatory (Volkswagen group).
+var vinModelYearWMIs = map[string]bool{
+	"TMB": true,
+	"VSS": true,
+	"WAU": true,
+	"WP0": true,
+	"WP1": true,
+	"WUA": true,
+	"WV1": true,
+	"WV2": true,
+	"WVG": true,
+	"WVW": true,
+}
+
+// Validates the length and the characters, and the check digit if it is mandatory.
+func (vin VIN) Validate() error {
+	if len(vin) != 17 {
+		return fmt.Errorf("%w: expected 17 characters, got %d", ErrInvalidVIN, len(vin))
+	}
+
+	for _, c := range []byte(vin) {
+		if strings.IndexByte(vinCharacters, c) < 0 {
+			return fmt.Errorf("%w: character %q is not allowed", ErrInvalidVIN, c)
+		}
+	}
+
+	if vin.HasCheckDigit() && vin.checkDigit() != vin[8] {
+		return fmt.Errorf("%w: check digit doesn't match", ErrInvalidVIN)
+	}
+
+	return nil
+}
+
+// Reports whether the 9th character is the check digit.
+// The check digit is mandatory for vehicles made for North America and China.
+func (vin VIN) HasCheckDigit() bool {
+	return len(vin) == 17 && ((vin[0] >= '1' && vin[0] <= '5') || vin[0] == 'L')
+}
+
+// Computes the check digit. Remainder 10 is written as X.
+func (vin VIN) checkDigit() byte {
+	sum := 0
+	for i, c := range []byte(vin) {
+		sum += vinWeights[i] * vinCharValue(c)
+	}
+
+	if sum%11 == 10 {
+		return 'X'
+	}
+
+	return byte('0' + sum%11)
+}
+
+// Values of letters A to Z used in check digit computation. Letters I, O and Q are not used.
+const vinLetterValues = "12345678.12345.7.923456789"
+
+// Returns the value of the character used in check digit computation.
+func vinCharValue(c byte) int {
+	if c >= '0' && c <= '9' {
+		return int(c - '0')
+	}
+
+	return int(vinLetterValues[c-'A'] - '0')
+}
+
+// Returns the world manufacturer identifier, or an empty string if the VIN is too short.
+func (vin VIN) WMI() string {
+	if len(vin) < 3 {
+		return ""
+	}
+
+	return string(vin[:3])
+}
+
+// Returns makes of vehicles produced under the WMI, or nil if the WMI is not known.
+func (vin VIN) Manufacturers() []string {
+	return vinManufacturers[vin.WMI()]
+}
+
+// Returns possible model years encoded in the 10th character, from 1980 to 2069.
+// The year is encoded in VINs with the mandatory check digit, and in VINs of manufacturers known to encode it.
+// Other manufacturers (e.g. most European ones) can use the 10th character freely, so nil is returned for them,
+// as well as for characters that don't encode the year.
+func (vin VIN) ModelYears() []int {
+	if !vin.HasCheckDigit() && !vinModelYearWMIs[vin.WMI()] {
+		return nil
+	}
+
+	index := strings.IndexByte(vinModelYearCharacters, vin[9])
+	if index < 0 {
+		return nil
+	}
+
+	return []int{1980 + index, 2010 + index, 2040 + index}
+}
+
+// Result of the VIN check against the other fields of the document.
+type VINCheck struct {
+	VIN          VIN
+	Error        string   `json:",omitempty"` // Reason why the VIN is not valid. Empty for valid VIN
+	CheckDigit   bool     // The check digit is mandatory and it is verified
+	WMI          string   // World manufacturer identifier
+	Manufacturer string   `json:",omitempty"` // Decoded make of the vehicle. Empty if the WMI is not known
+	ModelYear    int      `json:",omitempty"` // Decoded model year. Zero if the year is not encoded
+	Mismatches   []string `json:",omitempty"` // Names of document fields that don't match the decoded values
+}
+
+// Checks if the VIN is valid and all checks pass.
+func (check VINCheck) OK() bool {
+	return len(check.Error) == 0 && len(check.Mismatches) == 0
+}
+
+// Validates the VIN and compares it with the make and the year of production from the card.
+// The model year is chosen among the possible years as the closest one to the year of production,
+// or as the latest one until the next year if the year of production is not known.
+// The model year matches if it is equal to the year of production or the next year.
+// Empty make and year of production are not compared.
+func NewVINCheck(vin VIN, vehicleMake, yearOfProduction string, t time.Time) VINCheck {
+	check := VINCheck{VIN: vin}
+
+	err := vin.Validate()
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	check.CheckDigit = vin.HasCheckDigit()
+	check.WMI = vin.WMI()
+
+	manufacturers := vin.Manufacturers()
+	if len(manufacturers) > 0 {
+		check.Manufacturer = manufacturers[0]
+		if len(vehicleMake) > 0 && !matchesMake(vehicleMake, manufacturers) {
+			check.Mismatches = append(check.Mismatches, "VehicleMake")
+		}
+	}
+
+	year, err := strconv.Atoi(strings.TrimSpace(yearOfProduction))
+	if err != nil {
+		year = 0
+	}
+
+	for _, modelYear := range vin.ModelYears() {
+		if year > 0 && yearDistance(modelYear, year) < yearDistance(check.ModelYear, year) {
+			check.ModelYear = modelYear
+		} else if year == 0 && modelYear <= t.Year()+1 {
+			check.ModelYear = modelYear
+		}
+	}
+
+	if year > 0 && check.ModelYear > 0 && check.ModelYear != year && check.ModelYear != year+1 {
+		check.Mismatches = append(check.Mismatches, "YearOfProduction")
+	}
+
+	return check
+}
+
+// Checks if the make from the card contains one of the names, ignoring case, diacritics and punctuation.
+func matchesMake(vehicleMake string, names []string) bool {
+	normalize := func(s string) string {
+		return strings.ReplaceAll(mrzField(s), "<", "")
+	}
+
+	normalizedMake := normalize(vehicleMake)
+	for _, name := range names {
+		if strings.Contains(normalizedMake, normalize(name)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func yearDistance(a, b int) int {
+	if a < b {
+		return b - a
+	}
+
+	return a - b
+}
+
+func (doc *VehicleDocument) CheckVIN() VINCheck {
+	return NewVINCheck(VIN(doc.VehicleIdNumber), doc.VehicleMake, doc.YearOfProduction, time.Now())
+}
@@ -0,0 +1,101 @@
+package document_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ubavic/bas-celik/document"
+)
+
+func Test_VINValidate(t *testing.T) {
+	testCases := []struct {
+		vin           document.VIN
+		expectedError error
+	}{
+		{vin: "1M8GDM9AXKP042788"},
+		{vin: "WVWZZZ1KZAW123456"},
+		{vin: "1M8GDM9A1KP042788", expectedError: document.ErrInvalidVIN},
+		{vin: "WVWZZZ1KZAW12345", expectedError: document.ErrInvalidVIN},
+		{vin: "WVWZZZ1KZOW123456", expectedError: document.ErrInvalidVIN},
+		{vin: "wvwzzz1kzaw123456", expectedError: document.ErrInvalidVIN},
+	}
+
+	for _, testCase := range testCases {
+		err := testCase.vin.Validate()
+		if !errors.Is(err, testCase.expectedError) {
+			t.Errorf("Expected error %v for %s but got %v", testCase.expectedError, testCase.vin, err)
+		}
+	}
+}
+
+func Test_VINCheck(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		vin                  document.VIN
+		make                 string
+		yearOfProduction     string
+		expectedManufacturer string
+		expectedModelYear    int
+		expectedMismatches   []string
+	}{
+		{
+			vin:                  "WVWZZZ1KZAW123456",
+			make:                 "VOLKSWAGEN",
+			yearOfProduction:     "2009",
+			expectedManufacturer: "Volkswagen",
+			expectedModelYear:    2010,
+		},
+		{
+			vin:                  "WVWZZZ1KZAW123456",
+			make:                 "AUDI",
+			yearOfProduction:     "2015",
+			expectedManufacturer: "Volkswagen",
+			expectedModelYear:    2010,
+			expectedMismatches:   []string{"VehicleMake", "YearOfProduction"},
+		},
+		{
+			vin:                  "VF1BB05CF12345678",
+			make:                 "RENAULT",
+			yearOfProduction:     "2015",
+			expectedManufacturer: "Renault",
+		},
+		{
+			vin:                  "TMBZZZ1Z0Z1234567",
+			make:                 "ŠKODA",
+			expectedManufacturer: "Škoda",
+		},
+		{
+			vin:               "1M8GDM9AXKP042788",
+			expectedModelYear: 2019,
+		},
+		{
+			vin:                "1M8GDM9AXKP042788",
+			yearOfProduction:   "2015",
+			expectedModelYear:  2019,
+			expectedMismatches: []string{"YearOfProduction"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		check := document.NewVINCheck(testCase.vin, testCase.make, testCase.yearOfProduction, now)
+		if len(check.Error) > 0 {
+			t.Errorf("Unexpected error for %s: %s", testCase.vin, check.Error)
+			continue
+		}
+
+		if check.Manufacturer != testCase.expectedManufacturer {
+			t.Errorf("Expected manufacturer '%s' but got '%s'", testCase.expectedManufacturer, check.Manufacturer)
+		}
+
+		if check.ModelYear != testCase.expectedModelYear {
+			t.Errorf("Expected model year %d but got %d", testCase.expectedModelYear, check.ModelYear)
+		}
+
+		if !slices.Equal(check.Mismatches, testCase.expectedMismatches) {
+			t.Errorf("Expected mismatches %v but got %v", testCase.expectedMismatches, check.Mismatches)
+		}
+	}
+}
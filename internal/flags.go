@@ -1,12 +1,16 @@
 package internal
 
 import (
-	"encoding/hex"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/ebfe/scard"
+	"github.com/ubavic/bas-celik/card"
+	"github.com/ubavic/bas-celik/document"
 )
 
 var version string
@@ -14,15 +18,23 @@ var version string
 func ProcessFlags() (LaunchConfig, bool) {
 	launchCfg := LaunchConfig{}
 
-	atrFlag := flag.Bool("atr", false, "Print the ATR form the card and exit")
+	atrTablePath := flag.String("atrTable", "", "Load additional ATR table from the JSON file")
+	atrFlag := flag.Bool("atr", false, "Print the decoded ATR from the card and exit. If the -json flag is set, the decoded ATR is saved to the JSON file")
+	checkValidityFlag := flag.Bool("checkValidity", false, fmt.Sprintf("After saving the document, exit with code %d if the document is expired, with code %d if it expires in less than %d days, or with code %d if its expiry date is not available", ExitCodeExpired, ExitCodeExpiresSoon, document.ExpiresSoonDays, ExitCodeValidityUnknown))
+	euFlag := flag.Bool("eu", false, "Label fields of vehicle documents with the harmonised codes of the EU Directive 1999/37/EC in PDF, JSON and Excel files. Ignored for other cards")
+	exclusiveFlag := flag.Bool("exclusive", false, "Connect to the card in exclusive mode, so other applications can't access the card while it is read")
 	excelPath := flag.String("excel", "", "Set Excel export path")
 	jsonPath := flag.String("json", "", "Set JSON export path")
 	listFlag := flag.Bool("list", false, "List connected readers and exit")
+	mrzText := flag.String("mrz", "", fmt.Sprintf("After saving the document, compare the MRZ from the back of the ID card with the data from the chip, and exit with code %d if they don't match. Lines can be separated with spaces or new lines", ExitCodeMRZMismatch))
+	partsFlag := flag.String("parts", "", "Read only the listed parts of the document (comma separated): document, personal, residence, portrait, variablePersonal, administrative")
 	pdfPath := flag.String("pdf", "", "Set PDF export path.")
 	getValidUntilFromRfzo := flag.Bool("rfzoValidUntil", false, "Get the valid until date of medical card insurance from the RFZO API. Ignored for other cards")
+	timeout := flag.Duration("timeout", 0, "Abort reading if it isn't finished within the given duration (e.g. 30s). Zero means no timeout")
 	verboseFlag := flag.Bool("verbose", false, "Provide additional details in the terminal")
 	versionFlag := flag.Bool("version", false, "Display version information and exit")
 	readerIndex := flag.Uint("reader", 0, "Set reader")
+	reportPath := flag.String("report", "", "Save diagnostic report about the card to the file and exit. The report is saved as JSON if the path has .json extension")
 	flag.Parse()
 
 	if *versionFlag {
@@ -39,24 +51,113 @@ func ProcessFlags() (LaunchConfig, bool) {
 	}
 
 	if *atrFlag {
-		err := printATR(*readerIndex)
+		err := printATR(*readerIndex, *jsonPath)
 		if err != nil {
 			fmt.Println("Error reading ATR:", err)
 		}
 		return launchCfg, true
 	}
 
+	if len(*reportPath) > 0 {
+		err := writeReport(*readerIndex, *reportPath)
+		if err != nil {
+			fmt.Println("Error creating report:", err)
+		}
+		return launchCfg, true
+	}
+
+	parts, err := card.ParseParts(*partsFlag)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return launchCfg, true
+	}
+
+	if len(*mrzText) > 0 {
+		_, err := document.ParseMRZ(*mrzText)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return launchCfg, true
+		}
+	}
+
+	launchCfg.AtrTablePath = *atrTablePath
+	launchCfg.Parts = parts
 	launchCfg.JsonPath = *jsonPath
 	launchCfg.PdfPath = *pdfPath
 	launchCfg.ExcelPath = *excelPath
 	launchCfg.Verbose = *verboseFlag
+	launchCfg.Exclusive = *exclusiveFlag
+	launchCfg.EU = *euFlag
+	launchCfg.CheckValidity = *checkValidityFlag
+	launchCfg.MRZ = *mrzText
 	launchCfg.Reader = *readerIndex
+	launchCfg.Timeout = *timeout
 	launchCfg.GetValidUntilFromRfzo = *getValidUntilFromRfzo
 
 	return launchCfg, false
 }
 
-func printATR(reader uint) error {
+func printATR(reader uint, jsonPath string) error {
+	return withCard(reader, func(sCard *scard.Card, _ string) error {
+		smartCardStatus, err := sCard.Status()
+		if err != nil {
+			return fmt.Errorf("reading card %w", err)
+		}
+
+		atrInfo, err := card.ParseAtr(card.Atr(smartCardStatus.Atr))
+		if err != nil {
+			fmt.Println(card.Atr(smartCardStatus.Atr))
+			return fmt.Errorf("decoding ATR: %w", err)
+		}
+
+		if len(jsonPath) > 0 {
+			data, err := json.Marshal(atrInfo)
+			if err != nil {
+				return fmt.Errorf("encoding ATR: %w", err)
+			}
+
+			err = os.WriteFile(jsonPath, data, 0600)
+			if err != nil {
+				return fmt.Errorf("writing file %s: %w", jsonPath, err)
+			}
+
+			return nil
+		}
+
+		fmt.Println(atrInfo)
+
+		return nil
+	})
+}
+
+// Creates a diagnostic report for the card and saves it to the file.
+// The report is saved in JSON format if the path has `.json` extension, and in plain text otherwise.
+func writeReport(reader uint, path string) error {
+	return withCard(reader, func(sCard *scard.Card, readerName string) error {
+		report, err := card.CreateReport(sCard, readerName, version)
+		if err != nil {
+			return fmt.Errorf("creating report: %w", err)
+		}
+
+		data := []byte(report.Text())
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			data, err = report.Json()
+			if err != nil {
+				return fmt.Errorf("encoding report: %w", err)
+			}
+		}
+
+		err = os.WriteFile(path, data, 0600)
+		if err != nil {
+			return fmt.Errorf("writing file %s: %w", path, err)
+		}
+
+		return nil
+	})
+}
+
+// Connects to the card in the reader with the given index and calls f.
+func withCard(reader uint, f func(sCard *scard.Card, readerName string) error) error {
 	ctx, err := scard.EstablishContext()
 	if err != nil {
 		return fmt.Errorf("establishing context: %w", err)
@@ -84,14 +185,7 @@ func printATR(reader uint) error {
 
 	defer sCard.Disconnect(scard.LeaveCard)
 
-	smartCardStatus, err := sCard.Status()
-	if err != nil {
-		return fmt.Errorf("reading card %w", err)
-	}
-
-	fmt.Println(hex.EncodeToString(smartCardStatus.Atr))
-
-	return nil
+	return f(sCard, readersNames[reader])
 }
 
 func listReaders() error {
@@ -1,6 +1,11 @@
 package gui
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
@@ -19,13 +24,13 @@ func pageID(doc *document.IdDocument) *fyne.Container {
 	widthThird := (350 - 2*theme.Padding()) / 3
 
 	nameF := widgets.NewField(t("id.name"), doc.GetFullName(), 350)
-	birthDateF := widgets.NewField(t("id.birthDate"), doc.DateOfBirth, widthThird)
+	birthDateF := widgets.NewField(t("id.birthDate"), doc.DateOfBirth.Text, widthThird)
 	sexF := widgets.NewField(t("id.sex"), doc.Sex, widthThird)
 	personalNumberF := widgets.NewField(t("id.personalNumber"), doc.PersonalNumber, widthThird)
 	birthRow := container.New(layout.NewHBoxLayout(), sexF, birthDateF, personalNumberF)
 	birthPlaceF := widgets.NewField(t("id.birthPlace"), doc.GetFullPlaceOfBirth(), 350)
 	addressF := widgets.NewField(t("id.address"), doc.GetFullAddress(false), 350)
-	addressDateF := widgets.NewField(t("id.addressDate"), doc.AddressDate, 10)
+	addressDateF := widgets.NewField(t("id.addressDate"), doc.AddressDate.Text, 10)
 
 	personalInformationGroupObjects = []fyne.CanvasObject{nameF, birthRow, birthPlaceF, addressF, addressDateF}
 
@@ -53,8 +58,8 @@ func pageID(doc *document.IdDocument) *fyne.Container {
 
 	docGroupObjects = append(docGroupObjects, widgets.NewField(t("id.issuingAuthority"), doc.IssuingAuthority, 350))
 	documentNumberF := widgets.NewField(t("id.docRegNo"), doc.DocRegNo, widthThird)
-	issueDateF := widgets.NewField(t("id.issuingDate"), doc.IssuingDate, widthThird)
-	expiryDateF := widgets.NewField(t("id.expiryDate"), doc.ExpiryDate, widthThird)
+	issueDateF := widgets.NewField(t("id.issuingDate"), doc.IssuingDate.Text, widthThird)
+	expiryDateF := widgets.NewField(t("id.expiryDate"), doc.ExpiryDate.Text, widthThird)
 	docRow := container.New(layout.NewHBoxLayout(), documentNumberF, issueDateF, expiryDateF)
 	docGroupObjects = append(docGroupObjects, docRow)
 
@@ -73,7 +78,7 @@ func pageID(doc *document.IdDocument) *fyne.Container {
 func pageMedical(doc *document.MedicalDocument) *fyne.Container {
 	nameF := widgets.NewField(t("medical.fullName"), doc.GetFullName(), 350)
 	genderF := widgets.NewField(t("medical.gender"), doc.Gender, 170)
-	birthDateF := widgets.NewField(t("medical.dateOfBirth"), doc.DateOfBirth, 170)
+	birthDateF := widgets.NewField(t("medical.dateOfBirth"), doc.DateOfBirth.Text, 170)
 	birthRow := container.New(layout.NewHBoxLayout(), genderF, birthDateF)
 
 	personalNumberF := widgets.NewField(t("medical.personalNumber"), doc.PersonalNumber, 170)
@@ -98,7 +103,7 @@ func pageMedical(doc *document.MedicalDocument) *fyne.Container {
 	generalGroup := widgets.NewGroup(t("medical.generalInformation"), nameF, birthRow, idsRow, address1Row, address2Row, address3Row)
 
 	insuranceBasisF := widgets.NewField(t("medical.insuranceBasis"), doc.InsuranceBasisRZZO, 170)
-	insuranceStartDateF := widgets.NewField(t("medical.insuranceStartDate"), doc.InsuranceStartDate, 170)
+	insuranceStartDateF := widgets.NewField(t("medical.insuranceStartDate"), doc.InsuranceStartDate.Text, 170)
 	insuranceRow := container.New(layout.NewHBoxLayout(), insuranceBasisF, insuranceStartDateF)
 
 	insuranceDescriptionF := widgets.NewField(t("medical.insuranceDescription"), doc.InsuranceDescription, 350)
@@ -116,11 +121,11 @@ func pageMedical(doc *document.MedicalDocument) *fyne.Container {
 	carrierRow2 := container.New(layout.NewHBoxLayout(), carrierFamilyMemberF, carrierRelationshipF)
 	carrierGroup := widgets.NewGroup(t("medical.insuranceCarrierInformation"), carrierNameF, carrierRow1, carrierRow2)
 	cardNumber := widgets.NewField(t("medical.cardId"), doc.CardId, 270)
-	dateOfIssueF := widgets.NewField(t("medical.dateOfIssue"), doc.DateOfIssue, 170)
-	dateOfExpiryF := widgets.NewField(t("medical.dateOfExpiry"), doc.DateOfExpiry, 170)
+	dateOfIssueF := widgets.NewField(t("medical.dateOfIssue"), doc.DateOfIssue.Text, 170)
+	dateOfExpiryF := widgets.NewField(t("medical.dateOfExpiry"), doc.DateOfExpiry.Text, 170)
 	cardRow1 := container.New(layout.NewHBoxLayout(), dateOfIssueF, dateOfExpiryF)
 
-	validUntilF := widgets.NewField(t("medical.validUntil"), doc.ValidUntil, 170)
+	validUntilF := widgets.NewField(t("medical.validUntil"), doc.ValidUntil.Text, 170)
 	permanentlyValidF := widgets.NewField(t("medical.permanentlyValid"), localization.FormatYesNo(doc.PermanentlyValid, translation.CurrentLanguage()), 170)
 	cardRow2 := container.New(layout.NewHBoxLayout(), validUntilF, permanentlyValidF)
 
@@ -148,8 +153,8 @@ func pageVehicle(doc *document.VehicleDocument) *fyne.Container {
 	issuingStateF := widgets.NewField(t("vehicle.stateIssuing"), doc.StateIssuing, 220)
 	issuedByF := widgets.NewField(t("vehicle.authorityIssuing"), doc.AuthorityIssuing, 220)
 	issueRow := container.New(layout.NewHBoxLayout(), issuingStateF, issuedByF)
-	issuingDateF := widgets.NewField(t("vehicle.issuingDate"), doc.IssuingDate, 220)
-	expiryDateF := widgets.NewField(t("vehicle.expiryDate"), doc.ExpiryDate, 220)
+	issuingDateF := widgets.NewField(t("vehicle.issuingDate"), doc.IssuingDate.Text, 220)
+	expiryDateF := widgets.NewField(t("vehicle.expiryDate"), doc.ExpiryDate.Text, 220)
 	dateRow := container.New(layout.NewHBoxLayout(), issuingDateF, expiryDateF)
 	competentAuthorityF := widgets.NewField(t("vehicle.competentAuthority"), doc.CompetentAuthority, 350)
 	docIdF := widgets.NewField(t("vehicle.unambiguousNumber"), doc.UnambiguousNumber, 220)
@@ -192,39 +197,237 @@ func pageVehicle(doc *document.VehicleDocument) *fyne.Container {
 	colLeft := container.New(layout.NewVBoxLayout(), documentGroup, ownerGroup)
 
 	registrationNumberF := widgets.NewField(t("vehicle.registrationNumberOfVehicle"), doc.RegistrationNumberOfVehicle, 220)
-	dateOfFirstRegistrationF := widgets.NewField(t("vehicle.dateOfFirstRegistration"), doc.DateOfFirstRegistration, 220)
+	dateOfFirstRegistrationF := widgets.NewField(t("vehicle.dateOfFirstRegistration"), doc.DateOfFirstRegistration.Text, 220)
 	vehicleRow0 := container.New(layout.NewHBoxLayout(), registrationNumberF, dateOfFirstRegistrationF)
 
 	brandF := widgets.NewField(t("vehicle.vehicleMake"), doc.VehicleMake, 220)
 	modelF := widgets.NewField(t("vehicle.commercialDescription"), doc.CommercialDescription, 220)
 	vehicleRow1 := container.New(layout.NewHBoxLayout(), brandF, modelF)
 
+	vinF := widgets.NewField(t("vehicle.vehicleIdNumber"), doc.VehicleIdNumber, 220)
+	vinCheckF := widgets.NewField(t("vehicle.vinCheck"), "", 220)
+	if len(doc.VehicleIdNumber) > 0 {
+		vinCheckF = widgets.NewField(t("vehicle.vinCheck"), vinCheckText(doc.CheckVIN()), 220)
+	}
+	vinRow := container.New(layout.NewHBoxLayout(), vinF, vinCheckF)
+
 	colorF := widgets.NewField(t("vehicle.colourOfVehicle"), doc.ColourOfVehicle, 220)
 	yearOfProductionF := widgets.NewField(t("vehicle.yearOfProduction"), doc.YearOfProduction, 220)
 	vehicleRow2 := container.New(layout.NewHBoxLayout(), colorF, yearOfProductionF)
 
-	massF := widgets.NewField(t("vehicle.vehicleMass"), doc.VehicleMass, 220)
-	maximalAllowedMassF := widgets.NewField(t("vehicle.maximumPermissibleLadenMass"), doc.MaximumPermissibleLadenMass, 220)
+	massF := widgets.NewField(t("vehicle.vehicleMass"), quantityText(doc.GetVehicleMass(), doc.VehicleMass), 220)
+	maximalAllowedMassF := widgets.NewField(t("vehicle.maximumPermissibleLadenMass"), quantityText(doc.GetMaximumPermissibleLadenMass(), doc.MaximumPermissibleLadenMass), 220)
 	vehicleRow3 := container.New(layout.NewHBoxLayout(), massF, maximalAllowedMassF)
 
-	enginePowerF := widgets.NewField(t("vehicle.maximumNetPower"), doc.MaximumNetPower, 220)
-	powerMassRatioF := widgets.NewField(t("vehicle.powerWeightRatio"), doc.PowerWeightRatio, 220)
-	vehicleRow4 := container.New(layout.NewHBoxLayout(), enginePowerF, powerMassRatioF)
+	payloadF := widgets.NewField(t("vehicle.payload"), doc.GetPayload().String(), 220)
+	axlesF := widgets.NewField(t("vehicle.numberOfAxles"), doc.NumberOfAxles, 220)
+	vehicleRow4 := container.New(layout.NewHBoxLayout(), payloadF, axlesF)
+
+	enginePower := quantityText(doc.GetMaximumNetPower(), doc.MaximumNetPower)
+	if hp := doc.GetMaximumNetPowerHp(); hp.Valid {
+		enginePower += " (" + hp.String() + ")"
+	}
+	enginePowerF := widgets.NewField(t("vehicle.maximumNetPower"), enginePower, 220)
+	powerMassRatioF := widgets.NewField(t("vehicle.powerWeightRatio"), quantityText(doc.GetPowerWeightRatio(), doc.PowerWeightRatio), 220)
+	vehicleRow5 := container.New(layout.NewHBoxLayout(), enginePowerF, powerMassRatioF)
 
 	engineNumberF := widgets.NewField(t("vehicle.engineIdNumber"), doc.EngineIdNumber, 220)
-	engineCapacityF := widgets.NewField(t("vehicle.engineCapacity"), doc.EngineCapacity, 220)
-	vehicleRow5 := container.New(layout.NewHBoxLayout(), engineNumberF, engineCapacityF)
+	engineCapacityF := widgets.NewField(t("vehicle.engineCapacity"), quantityText(doc.GetEngineCapacity(), doc.EngineCapacity), 220)
+	vehicleRow6 := container.New(layout.NewHBoxLayout(), engineNumberF, engineCapacityF)
 
 	seatsF := widgets.NewField(t("vehicle.numberOfSeats"), doc.NumberOfSeats, 220)
 	standingF := widgets.NewField(t("vehicle.numberOfStandingPlaces"), doc.NumberOfStandingPlaces, 220)
-	vehicleRow6 := container.New(layout.NewHBoxLayout(), seatsF, standingF)
+	vehicleRow7 := container.New(layout.NewHBoxLayout(), seatsF, standingF)
 
 	insuranceHolderGroup := widgets.NewGroup(t("vehicle.vehicleInformation"),
-		vehicleRow0, vehicleRow1, vehicleRow2, vehicleRow3,
-		vehicleRow4, vehicleRow5, vehicleRow6,
+		vehicleRow0, vehicleRow1, vinRow, vehicleRow2, vehicleRow3, vehicleRow4,
+		vehicleRow5, vehicleRow6, vehicleRow7,
 	)
 
 	colRight := container.New(layout.NewVBoxLayout(), insuranceHolderGroup)
 
 	return container.New(layout.NewHBoxLayout(), colLeft, colRight)
 }
+
+func pageTachograph(doc *document.TachographDocument) *fyne.Container {
+	nameF := widgets.NewField(t("tachograph.name"), doc.GetFullName(), 350)
+	birthDateF := widgets.NewField(t("tachograph.dateOfBirth"), doc.DateOfBirth.Text, 170)
+	languageF := widgets.NewField(t("tachograph.preferredLanguage"), doc.PreferredLanguage, 170)
+	holderRow := container.New(layout.NewHBoxLayout(), birthDateF, languageF)
+	holderGroup := widgets.NewGroup(t("tachograph.holderInformation"), nameF, holderRow)
+
+	cardNumberF := widgets.NewField(t("tachograph.cardNumber"), doc.CardNumber, 170)
+	memberStateF := widgets.NewField(t("tachograph.issuingMemberState"), doc.CardIssuingMemberState, 170)
+	cardRow1 := container.New(layout.NewHBoxLayout(), cardNumberF, memberStateF)
+	authorityF := widgets.NewField(t("tachograph.issuingAuthority"), doc.CardIssuingAuthorityName, 350)
+	issueDateF := widgets.NewField(t("tachograph.issueDate"), doc.CardIssueDate.Text, 170)
+	expiryDateF := widgets.NewField(t("tachograph.expiryDate"), doc.CardExpiryDate.Text, 170)
+	cardRow2 := container.New(layout.NewHBoxLayout(), issueDateF, expiryDateF)
+	cardGroup := widgets.NewGroup(t("tachograph.cardInformation"), cardRow1, authorityF, cardRow2)
+
+	colLeft := container.New(layout.NewVBoxLayout(), holderGroup, cardGroup)
+
+	licenceNumberF := widgets.NewField(t("tachograph.licenceNumber"), doc.DrivingLicenceNumber, 170)
+	licenceNationF := widgets.NewField(t("tachograph.licenceNation"), doc.DrivingLicenceIssuingNation, 170)
+	licenceRow := container.New(layout.NewHBoxLayout(), licenceNumberF, licenceNationF)
+	licenceAuthorityF := widgets.NewField(t("tachograph.licenceAuthority"), doc.DrivingLicenceIssuingAuthority, 350)
+	licenceGroup := widgets.NewGroup(t("tachograph.licenceInformation"), licenceRow, licenceAuthorityF)
+
+	lastActivity := ""
+	if len(doc.ActivityDays) > 0 {
+		lastActivity = doc.ActivityDays[len(doc.ActivityDays)-1].Date.Text
+	}
+
+	activityDaysF := widgets.NewField(t("tachograph.activityDays"), fmt.Sprint(len(doc.ActivityDays)), 170)
+	lastActivityF := widgets.NewField(t("tachograph.lastActivity"), lastActivity, 170)
+	activityRow := container.New(layout.NewHBoxLayout(), activityDaysF, lastActivityF)
+	activityGroup := widgets.NewGroup(t("tachograph.activityInformation"), activityRow)
+
+	colRight := container.New(layout.NewVBoxLayout(), licenceGroup, activityGroup)
+
+	return container.New(layout.NewHBoxLayout(), colLeft, colRight)
+}
+
+// Creates the group with card fields that are not shown on the document page.
+// Nil is returned if there are no such fields.
+func extraGroup(doc document.Document) fyne.CanvasObject {
+	holder, ok := doc.(document.ExtraHolder)
+	if !ok || len(holder.GetExtra()) == 0 {
+		return nil
+	}
+
+	extra := holder.GetExtra()
+	fields := []fyne.CanvasObject{}
+	for _, key := range extra.Keys() {
+		fields = append(fields, widgets.NewField(key, extra.Text(key), 220))
+	}
+
+	return widgets.NewGroup(t("ui.additionalData"), container.NewGridWithColumns(3, fields...))
+}
+
+// Creates the badge that warns that the document is expired or expires soon.
+// Nil is returned for valid documents and documents without an expiry date.
+func validityBadge(doc document.Document) fyne.CanvasObject {
+	checker, ok := doc.(document.ValidityChecker)
+	if !ok {
+		return nil
+	}
+
+	validity := checker.ValidityAt(time.Now())
+
+	var badge *widget.Label
+	switch validity.Status {
+	case document.Expired:
+		badge = widget.NewLabel(fmt.Sprintf(t("ui.validity.expired"), validity.ExpiryDate))
+		badge.Importance = widget.DangerImportance
+	case document.ExpiresSoon:
+		badge = widget.NewLabel(fmt.Sprintf(t("ui.validity.expiresSoon"), validity.ExpiryDate, validity.DaysRemaining))
+		badge.Importance = widget.WarningImportance
+	default:
+		return nil
+	}
+
+	badge.TextStyle.Bold = true
+	return badge
+}
+
+// Creates badges that warn about invalid personal numbers (JMBG)
+// and personal numbers that don't match other data from the card.
+func jmbgBadges(doc document.Document) []fyne.CanvasObject {
+	checker, ok := doc.(document.JMBGChecker)
+	if !ok {
+		return nil
+	}
+
+	badges := []fyne.CanvasObject{}
+	for _, check := range checker.CheckJMBG() {
+		if check.OK() {
+			continue
+		}
+
+		var badge *widget.Label
+		if len(check.Error) > 0 {
+			badge = widget.NewLabel(fmt.Sprintf(t("ui.jmbg.invalid"), check.JMBG))
+		} else {
+			fields := make([]string, len(check.Mismatches))
+			for i, field := range check.Mismatches {
+				fields[i] = t("ui.jmbg.field." + field)
+			}
+			badge = widget.NewLabel(fmt.Sprintf(t("ui.jmbg.mismatch"), check.JMBG, strings.Join(fields, ", ")))
+		}
+
+		badge.Importance = widget.WarningImportance
+		badge.TextStyle.Bold = true
+		badges = append(badges, badge)
+	}
+
+	return badges
+}
+
+// Returns the quantity with the unit, or the text from the card if it can't be parsed.
+func quantityText(quantity document.Quantity, text string) string {
+	if !quantity.Valid {
+		return text
+	}
+
+	return quantity.String()
+}
+
+// Describes the result of the VIN check, e.g. "valid (Volkswagen, 2015)" or "doesn't match: make".
+func vinCheckText(check document.VINCheck) string {
+	if len(check.Error) > 0 {
+		return t("ui.vin.notValid")
+	}
+
+	if len(check.Mismatches) > 0 {
+		return t("ui.vin.notMatching") + ": " + vinMismatchesText(check)
+	}
+
+	decoded := []string{}
+	if len(check.Manufacturer) > 0 {
+		decoded = append(decoded, check.Manufacturer)
+	}
+	if check.ModelYear > 0 {
+		decoded = append(decoded, strconv.Itoa(check.ModelYear))
+	}
+
+	if len(decoded) == 0 {
+		return t("ui.vin.valid")
+	}
+
+	return t("ui.vin.valid") + " (" + strings.Join(decoded, ", ") + ")"
+}
+
+// Returns translated names of the fields that don't match the VIN.
+func vinMismatchesText(check document.VINCheck) string {
+	fields := make([]string, len(check.Mismatches))
+	for i, field := range check.Mismatches {
+		fields[i] = t("ui.vin.field." + field)
+	}
+
+	return strings.Join(fields, ", ")
+}
+
+// Creates the warning about the VIN that is not valid or doesn't match the vehicle document.
+func vinBadge(doc document.Document) fyne.CanvasObject {
+	vehicleDoc, ok := doc.(*document.VehicleDocument)
+	if !ok || len(vehicleDoc.VehicleIdNumber) == 0 {
+		return nil
+	}
+
+	check := vehicleDoc.CheckVIN()
+	if check.OK() {
+		return nil
+	}
+
+	var badge *widget.Label
+	if len(check.Error) > 0 {
+		badge = widget.NewLabel(fmt.Sprintf(t("ui.vin.invalid"), check.VIN, check.Error))
+	} else {
+		badge = widget.NewLabel(fmt.Sprintf(t("ui.vin.mismatch"), check.VIN, vinMismatchesText(check)))
+	}
+
+	badge.Importance = widget.WarningImportance
+	badge.TextStyle.Bold = true
+	return badge
+}
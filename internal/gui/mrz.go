@@ -0,0 +1,69 @@
+package gui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/ubavic/bas-celik/document"
+)
+
+// Translation identifiers of the fields reported by document.IdDocument.CompareMRZ.
+var mrzFieldLabels = map[string]string{
+	"CheckDigits":    "ui.mrz.checkDigits",
+	"DocumentType":   "id.documentName",
+	"DocRegNo":       "id.docRegNo",
+	"PersonalNumber": "id.personalNumber",
+	"DateOfBirth":    "id.birthDate",
+	"Sex":            "id.sex",
+	"ExpiryDate":     "id.expiryDate",
+	"Surname":        "id.name",
+	"GivenName":      "id.name",
+}
+
+// Shows the dialog for comparing the scanned or typed MRZ with the data from the chip.
+func compareMRZHandler(doc *document.IdDocument) func() {
+	return func() {
+		entry := widget.NewMultiLineEntry()
+		entry.SetPlaceHolder(doc.MRZ().String())
+		entry.SetMinRowsVisible(3)
+		entry.TextStyle.Monospace = true
+
+		items := []*widget.FormItem{
+			widget.NewFormItem("", widget.NewLabel(t("ui.mrz.prompt"))),
+			widget.NewFormItem("MRZ", entry),
+		}
+
+		dialog.ShowForm(t("ui.mrz.compare"), t("ui.mrz.compare"), t("ui.mrz.cancel"), items, func(compare bool) {
+			if compare {
+				showMRZComparison(doc, entry.Text)
+			}
+		}, state.window)
+	}
+}
+
+func showMRZComparison(doc *document.IdDocument, text string) {
+	mrz, err := document.ParseMRZ(text)
+	if err != nil {
+		dialog.ShowInformation(t("ui.mrz.compare"), fmt.Sprintf(t("ui.mrz.invalid"), err), state.window)
+		return
+	}
+
+	mismatches := doc.CompareMRZ(mrz)
+	if len(mismatches) == 0 {
+		dialog.ShowInformation(t("ui.mrz.compare"), t("ui.mrz.match"), state.window)
+		return
+	}
+
+	fields := []string{}
+	for _, mismatch := range mismatches {
+		label := t(mrzFieldLabels[mismatch])
+		if !slices.Contains(fields, label) {
+			fields = append(fields, label)
+		}
+	}
+
+	dialog.ShowInformation(t("ui.mrz.compare"), fmt.Sprintf(t("ui.mrz.mismatch"), strings.Join(fields, ", ")), state.window)
+}
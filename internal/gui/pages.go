@@ -0,0 +1,38 @@
+package gui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"github.com/ubavic/bas-celik/document"
+	"github.com/ubavic/bas-celik/pages"
+)
+
+// Registers pages of the documents read from the supported cards.
+func init() {
+	pages.Register(pages.Page[*document.IdDocument]{
+		Title:   "ui.tab.id",
+		Content: pageID,
+		Buttons: func(doc *document.IdDocument) []fyne.CanvasObject {
+			return []fyne.CanvasObject{widget.NewButton(t("ui.mrz.compare"), compareMRZHandler(doc))}
+		},
+	})
+	pages.Register(pages.Page[*document.MedicalDocument]{
+		Title:   "ui.tab.medical",
+		Content: pageMedical,
+		Buttons: func(doc *document.MedicalDocument) []fyne.CanvasObject {
+			return []fyne.CanvasObject{widget.NewButton(t("ui.update"), updateMedicalDocHandler(doc))}
+		},
+	})
+	pages.Register(pages.Page[*document.VehicleDocument]{
+		Title:   "ui.tab.vehicle",
+		Content: pageVehicle,
+		Buttons: func(doc *document.VehicleDocument) []fyne.CanvasObject {
+			euDoc := &document.EUVehicleDocument{Vehicle: doc}
+			return []fyne.CanvasObject{
+				widget.NewButton(t("ui.saveXlsxEu"), saveXlsx(euDoc)),
+				widget.NewButton(t("ui.savePdfEu"), savePdf(euDoc)),
+			}
+		},
+	})
+	pages.Register(pages.Page[*document.TachographDocument]{Title: "ui.tab.tachograph", Content: pageTachograph})
+}
@@ -1,15 +1,22 @@
 package gui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"fyne.io/fyne/v2/dialog"
 	"github.com/ebfe/scard"
 	"github.com/ubavic/bas-celik/card"
 	"github.com/ubavic/bas-celik/document"
 	"github.com/ubavic/bas-celik/internal/logger"
 )
 
-func connectToCard(selectedReader string, ctx *scard.Context) {
+// Maximal duration of a single card read.
+const cardReadTimeout = 60 * time.Second
+
+func connectToCard(readCtx context.Context, selectedReader string, ctx *scard.Context) {
 	readers, _ := ctx.ListReaders()
 	if selectedReader == "" || len(readers) == 0 {
 		setStartPage("error.noReader", "error.noReaderExplanation", nil)
@@ -18,9 +25,23 @@ func connectToCard(selectedReader string, ctx *scard.Context) {
 
 	setStartPage("poller.connectingReader", "", nil)
 
-	sCard, err := ctx.Connect(selectedReader, scard.ShareShared, scard.ProtocolAny)
+	shareMode := scard.ShareShared
+	if state.app.Preferences().BoolWithFallback(exclusiveAccessPreferenceKey, false) {
+		shareMode = scard.ShareExclusive
+	}
+
+	sCard, err := ctx.Connect(selectedReader, shareMode, scard.ProtocolAny)
 	if err == nil {
-		tryToProcessCard(sCard)
+		readCtx, cancel := context.WithTimeout(readCtx, cardReadTimeout)
+		defer cancel()
+
+		// When the read is aborted, the card is disconnected, so that a stuck reader doesn't block the poller.
+		stopDisconnect := context.AfterFunc(readCtx, func() {
+			sCard.Disconnect(scard.LeaveCard)
+		})
+
+		tryToProcessCard(readCtx, sCard, shareMode, selectedReader)
+		stopDisconnect()
 	} else {
 		state.mu.Lock()
 		state.cardDocument = nil
@@ -34,39 +55,83 @@ func connectToCard(selectedReader string, ctx *scard.Context) {
 	}
 }
 
-func tryToProcessCard(sCard *scard.Card) bool {
+func tryToProcessCard(ctx context.Context, sCard *scard.Card, shareMode scard.ShareMode, readerName string) bool {
 	loaded := false
 
 	setStartPage("poller.readingFromCard", "", nil)
 
-	cardDoc, err := card.DetectCardDocument(sCard)
-	if cardDoc != nil {
-		logger.Info("ATR read: " + cardDoc.Atr().String())
+	session := card.NewSession(sCard, shareMode, card.DefaultRetries)
+	session.OnRetry = func(attempt int, err error) {
+		logger.Info(fmt.Sprintf("card connection interrupted (%v), reconnecting (attempt %d/%d)", err, attempt, card.DefaultRetries))
 	}
-	if err != nil {
+
+	cardDocs, err := card.DetectCardDocumentsContext(ctx, session)
+	if len(cardDocs) > 0 {
+		logger.Info("ATR read: " + cardDocs[0].Atr().String())
+	}
+	if errors.Is(err, context.Canceled) {
+		logger.Info("card read canceled")
+	} else if err != nil {
 		message := ""
-		if err == card.ErrUnknownCard {
+		if errors.Is(err, card.ErrUnknownCard) {
 			message = "error.unknownCard"
+		} else if errors.Is(err, card.ErrReadTimeout) {
+			message = "error.readTimeout"
 		}
 		setStartPage(
 			"error.readingCard",
 			message,
 			fmt.Errorf("reading from card: %w", err))
+
+		if errors.Is(err, card.ErrUnknownCard) {
+			offerReport(sCard, readerName)
+		}
 	} else {
+		// PIN can be changed only on the ID card, so it is preferred over other documents.
+		cardDoc := cardDocs[0]
+		for _, c := range cardDocs {
+			if _, ok := c.(*card.Gemalto); ok {
+				cardDoc = c
+			}
+		}
+
 		state.mu.Lock()
 		state.cardDocument = cardDoc
 		state.mu.Unlock()
 
-		doc, err := initCardAndReadDoc(cardDoc)
-		if err != nil {
+		docs := []document.Document{}
+		for _, c := range cardDocs {
+			c.SetReadOptions(card.ReadOptions{Progress: setProgress})
+
+			var doc document.Document
+			doc, err = initCardAndReadDoc(ctx, session, c)
+			if err != nil {
+				break
+			}
+
+			docs = append(docs, doc)
+		}
+
+		if errors.Is(err, context.Canceled) {
+			logger.Info("card read canceled")
+		} else if err != nil {
+			message := ""
+			if errors.Is(err, card.ErrReadTimeout) {
+				message = "error.readTimeout"
+			}
+
 			setStartPage(
 				"error.readingCard",
-				"",
+				message,
 				fmt.Errorf("reading from card: %w", err))
 		} else {
 			setStatus("poller.documentRead", nil)
-			setUI(doc)
+			setUI(docs...)
 			loaded = true
+
+			if card.ShouldReportAtr(cardDoc) {
+				showUnknownAtrNotice(cardDoc.Atr())
+			}
 		}
 
 		switch cardDoc.(type) {
@@ -80,13 +145,18 @@ func tryToProcessCard(sCard *scard.Card) bool {
 	return loaded
 }
 
-func initCardAndReadDoc(cardDoc card.CardDocument) (document.Document, error) {
-	err := cardDoc.InitCard()
-	if err != nil {
-		return nil, err
-	}
+// Initializes and reads the card inside a transaction, so other applications can't interleave their commands.
+func initCardAndReadDoc(ctx context.Context, session *card.Session, cardDoc card.CardDocument) (document.Document, error) {
+	session.SetInit(cardDoc.InitCard)
+
+	err := session.Transaction(func() error {
+		err := cardDoc.InitCardContext(ctx)
+		if err != nil {
+			return err
+		}
 
-	err = cardDoc.ReadCard()
+		return cardDoc.ReadCardContext(ctx)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -98,3 +168,9 @@ func initCardAndReadDoc(cardDoc card.CardDocument) (document.Document, error) {
 
 	return doc, nil
 }
+
+// Informs the user that the card was detected by probing, and that its ATR should be reported.
+func showUnknownAtrNotice(atr card.Atr) {
+	logger.Info("unknown ATR: " + atr.String())
+	dialog.ShowInformation(t("ui.unknownAtr"), fmt.Sprintf(t("ui.unknownAtrExplanation"), atr), state.window)
+}
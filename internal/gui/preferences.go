@@ -10,6 +10,7 @@ import (
 const themePreferenceKey = "color-theme"
 const languagePreferenceKey = "language"
 const lastUsedDirectoryKey = "last-used-directory"
+const exclusiveAccessPreferenceKey = "exclusive-access"
 
 func showSetupBox(win fyne.Window, app fyne.App) func() {
 	preferences := app.Preferences()
@@ -28,9 +29,13 @@ func showSetupBox(win fyne.Window, app fyne.App) func() {
 		)
 		languageSelect.SetSelectedIndex(language)
 
+		exclusiveCheck := widget.NewCheck("", func(b bool) {})
+		exclusiveCheck.SetChecked(preferences.BoolWithFallback(exclusiveAccessPreferenceKey, false))
+
 		formItems := []*widget.FormItem{
 			{Text: t("preference.theme"), Widget: themeSelect},
 			{Text: t("preference.language"), Widget: languageSelect},
+			{Text: t("preference.exclusiveAccess"), Widget: exclusiveCheck},
 			{Text: "", Widget: &widgets.Spacer{}},
 		}
 
@@ -41,6 +46,7 @@ func showSetupBox(win fyne.Window, app fyne.App) func() {
 
 			preferences.SetInt(themePreferenceKey, themeSelect.SelectedIndex())
 			preferences.SetInt(languagePreferenceKey, languageSelect.SelectedIndex())
+			preferences.SetBool(exclusiveAccessPreferenceKey, exclusiveCheck.Checked)
 
 			dialog.ShowInformation(t("preference.saved"), t("preference.startAgain"), win)
 		}
@@ -1,8 +1,10 @@
 package reader
 
 import (
+	"context"
 	"fmt"
 	"slices"
+	"sync"
 	"sync/atomic"
 
 	"github.com/ebfe/scard"
@@ -18,7 +20,9 @@ type ReaderPoller struct {
 	currentReader       string
 	readers             []string
 	readerPollerStarted atomic.Bool
-	onCardEvent         func(string, *scard.Context)
+	onCardEvent         func(context.Context, string, *scard.Context)
+	readMu              sync.Mutex
+	cancelRead          context.CancelFunc
 }
 
 type ReaderLister interface {
@@ -26,7 +30,9 @@ type ReaderLister interface {
 	HookReaderChange(func(string))
 }
 
-func NewPoller(readerLister ReaderLister, onCardEvent func(string, *scard.Context)) (*ReaderPoller, error) {
+// Creates the poller that calls onCardEvent on each reader change and each card insertion or removal.
+// Context passed to onCardEvent is canceled on the next event, or when the poller is canceled.
+func NewPoller(readerLister ReaderLister, onCardEvent func(context.Context, string, *scard.Context)) (*ReaderPoller, error) {
 	if created {
 		panic("you can create only single instance of ReaderPoller")
 	}
@@ -57,7 +63,7 @@ func NewPoller(readerLister ReaderLister, onCardEvent func(string, *scard.Contex
 }
 
 func (rp *ReaderPoller) StartPoller() {
-	rp.onCardEvent("", rp.singleReaderContext)
+	rp.onCardEvent(rp.newReadContext(), "", rp.singleReaderContext)
 	go rp.pollReaders()
 }
 
@@ -108,7 +114,7 @@ func (rp *ReaderPoller) SetReader(newReader string) {
 		rp.readerPollerStarted.Store(false)
 		rp.singleReaderContext.Cancel()
 	}
-	rp.onCardEvent(newReader, rp.singleReaderContext)
+	rp.onCardEvent(rp.newReadContext(), newReader, rp.singleReaderContext)
 	go rp.readerPoller(newReader)
 }
 
@@ -136,11 +142,39 @@ func (rp *ReaderPoller) readerPoller(selectedReader string) {
 			return
 		}
 
-		rp.onCardEvent(selectedReader, rp.singleReaderContext)
+		rp.onCardEvent(rp.newReadContext(), selectedReader, rp.singleReaderContext)
+	}
+}
+
+// Cancels the context of the previous card event, and creates a context for the new one.
+func (rp *ReaderPoller) newReadContext() context.Context {
+	rp.readMu.Lock()
+	defer rp.readMu.Unlock()
+
+	if rp.cancelRead != nil {
+		rp.cancelRead()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rp.cancelRead = cancel
+
+	return ctx
+}
+
+// Cancels the context of the last card event, aborting the card read in progress.
+func (rp *ReaderPoller) cancelCardRead() {
+	rp.readMu.Lock()
+	defer rp.readMu.Unlock()
+
+	if rp.cancelRead != nil {
+		rp.cancelRead()
+		rp.cancelRead = nil
 	}
 }
 
 func CancelReaderPoler() {
+	createdPoller.cancelCardRead()
+
 	if createdPoller.readerPollerStarted.Load() {
 		createdPoller.readerPollerStarted.Store(false)
 		createdPoller.singleReaderContext.Cancel()
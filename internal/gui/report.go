@@ -0,0 +1,71 @@
+package gui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"github.com/ubavic/bas-celik/card"
+)
+
+// Creates a diagnostic report while the unknown card is still connected,
+// and offers the user to save it.
+func offerReport(sc card.Card, readerName string) {
+	report, err := card.CreateReport(sc, readerName, state.version)
+	if err != nil {
+		setStatus("error.creatingReport", fmt.Errorf("creating report: %w", err))
+		return
+	}
+
+	dialog.ShowConfirm(t("report.title"), t("report.question"), func(create bool) {
+		if create {
+			saveReport(report)
+		}
+	}, state.window)
+}
+
+func saveReport(report *card.Report) {
+	data, err := report.Json()
+	if err != nil {
+		setStatus("error.creatingReport", fmt.Errorf("encoding report: %w", err))
+		return
+	}
+
+	dialog := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+		if err != nil {
+			setStatus("error.writingReport", fmt.Errorf("writing report: %w", err))
+			return
+		}
+
+		if w == nil {
+			return
+		}
+
+		saveLastUsedDirectory(w.URI())
+
+		_, err = w.Write(data)
+		if err != nil {
+			setStatus("error.writingReport", fmt.Errorf("writing report: %w", err))
+			return
+		}
+
+		err = w.Close()
+		if err != nil {
+			setStatus("error.writingReport", fmt.Errorf("writing report: %w", err))
+			return
+		}
+
+		setStatus("report.saved", nil)
+	}, state.window)
+
+	dialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	dialog.SetFileName("bas-celik-report-" + report.Atr + ".json")
+
+	lastUsedDirectoryURI := getLastUsedDirectory()
+	if lastUsedDirectoryURI != nil {
+		dialog.SetLocation(lastUsedDirectoryURI)
+	}
+
+	dialog.Show()
+}
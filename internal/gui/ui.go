@@ -18,6 +18,7 @@ import (
 	"github.com/ubavic/bas-celik/internal/gui/translation"
 	"github.com/ubavic/bas-celik/internal/gui/widgets"
 	"github.com/ubavic/bas-celik/internal/logger"
+	"github.com/ubavic/bas-celik/pages"
 )
 
 type State struct {
@@ -31,6 +32,8 @@ type State struct {
 	mainContainer *fyne.Container
 	statusBar     *widgets.StatusBar
 	cardDocument  card.CardDocument
+	documents     []document.Document
+	version       string
 }
 
 var state State
@@ -73,6 +76,7 @@ func StartGui(version string) {
 		statusBar:     statusBar,
 		mainPage:      mainPage,
 		mainContainer: mainContainer,
+		version:       version,
 	}
 
 	win.SetContent(mainContainer)
@@ -86,22 +90,59 @@ func StartGui(version string) {
 	win.ShowAndRun()
 }
 
-func setUI(doc document.Document) {
+// Shows the documents read from the card.
+// When the card holds multiple documents, each document is shown in a separate tab.
+func setUI(docs ...document.Document) {
 	state.mu.Lock()
 	defer state.mu.Unlock()
 
-	var page *fyne.Container
+	state.documents = docs
+
+	var page fyne.CanvasObject
+	if len(docs) == 1 {
+		page = documentPage(docs[0])
+	} else {
+		tabs := container.NewAppTabs()
+		for _, doc := range docs {
+			tabs.Append(container.NewTabItem(documentTitle(doc), documentPage(doc)))
+		}
+		page = tabs
+	}
+
+	state.mainPage.RemoveAll()
+	state.mainPage.Add(page)
+
+	state.startPage.Hide()
+	state.mainPage.Show()
+
+	state.window.Resize(state.mainContainer.MinSize())
+}
+
+// Creates the page with the document and the buttons for saving it.
+// Documents without a registered page are shown only with the buttons.
+func documentPage(doc document.Document) *fyne.Container {
+	objects := []fyne.CanvasObject{}
 	buttonBarObjects := []fyne.CanvasObject{state.statusBar, layout.NewSpacer()}
 
-	switch doc := doc.(type) {
-	case *document.IdDocument:
-		page = pageID(doc)
-	case *document.MedicalDocument:
-		updateButton := widget.NewButton(t("ui.update"), updateMedicalDocHandler(doc))
-		buttonBarObjects = append(buttonBarObjects, updateButton)
-		page = pageMedical(doc)
-	case *document.VehicleDocument:
-		page = pageVehicle(doc)
+	if badge := validityBadge(doc); badge != nil {
+		objects = append(objects, badge)
+	}
+	objects = append(objects, jmbgBadges(doc)...)
+	if badge := vinBadge(doc); badge != nil {
+		objects = append(objects, badge)
+	}
+
+	page, ok := pages.Lookup(doc)
+	if ok {
+		objects = append(objects, page.Content(doc))
+
+		if page.Buttons != nil {
+			buttonBarObjects = append(buttonBarObjects, page.Buttons(doc)...)
+		}
+	}
+
+	if extra := extraGroup(doc); extra != nil {
+		objects = append(objects, extra)
 	}
 
 	savePdfButton := widget.NewButton(t("ui.savePdf"), savePdf(doc))
@@ -109,15 +150,23 @@ func setUI(doc document.Document) {
 	buttonBarObjects = append(buttonBarObjects, saveXlsxButton, savePdfButton)
 
 	buttonBar := container.New(layout.NewHBoxLayout(), buttonBarObjects...)
+	objects = append(objects, buttonBar)
 
-	state.mainPage.RemoveAll()
-	state.mainPage.Add(page)
-	state.mainPage.Add(buttonBar)
+	return container.New(layout.NewVBoxLayout(), objects...)
+}
 
-	state.startPage.Hide()
-	state.mainPage.Show()
+func documentTitle(doc document.Document) string {
+	page, ok := pages.Lookup(doc)
+	if !ok {
+		return ""
+	}
 
-	state.window.Resize(state.mainContainer.MinSize())
+	title := t(page.Title)
+	if len(title) == 0 {
+		return page.Title
+	}
+
+	return title
 }
 
 func setStartPage(statusId, explanationId string, err error) {
@@ -149,6 +198,16 @@ func setStartPage(statusId, explanationId string, err error) {
 	state.window.Resize(state.mainContainer.MinSize())
 }
 
+// Shows progress of the card read on the start page.
+func setProgress(progress card.Progress) {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	explanation := fmt.Sprintf(t("poller.readingProgress"), progress.File, progress.FileCount)
+	state.startPage.SetProgress(progress.Fraction(), explanation)
+	state.startPage.Refresh()
+}
+
 func setStatus(statusId string, err error) {
 	isError := false
 	if err != nil {
@@ -11,15 +11,18 @@ import (
 
 type StartPage struct {
 	widget.BaseWidget
-	status      string
-	explanation string
-	err         bool
+	status       string
+	explanation  string
+	err          bool
+	progress     float64
+	showProgress bool
 }
 
 type StartPageRenderer struct {
 	page            *StartPage
 	statusText      *canvas.Text
 	explanationText *canvas.Text
+	progressBar     *widget.ProgressBar
 	container       *fyne.Container
 }
 
@@ -37,6 +40,14 @@ func (sb *StartPage) SetStatus(status, explanation string, err bool) {
 	sb.status = status
 	sb.explanation = explanation
 	sb.err = err
+	sb.showProgress = false
+}
+
+// Shows the progress bar with the given value (between 0 and 1) below the status.
+func (sb *StartPage) SetProgress(progress float64, explanation string) {
+	sb.progress = progress
+	sb.explanation = explanation
+	sb.showProgress = true
 }
 
 func (sb *StartPage) CreateRenderer() fyne.WidgetRenderer {
@@ -48,13 +59,17 @@ func (sb *StartPage) CreateRenderer() fyne.WidgetRenderer {
 	explanationText.TextSize = 11
 	explanationText.Color = theme.Color(theme.ColorNameForeground)
 
-	box := container.New(layout.NewVBoxLayout(), statusText, explanationText)
+	progressBar := widget.NewProgressBar()
+	progressBar.Hide()
+
+	box := container.New(layout.NewVBoxLayout(), statusText, explanationText, progressBar)
 	container := container.New(layout.NewCenterLayout(), box)
 
 	return &StartPageRenderer{
 		page:            sb,
 		statusText:      statusText,
 		explanationText: explanationText,
+		progressBar:     progressBar,
 		container:       container,
 	}
 }
@@ -69,6 +84,13 @@ func (r *StartPageRenderer) Refresh() {
 		r.statusText.Color = theme.Color(theme.ColorNameForeground)
 	}
 
+	if r.page.showProgress {
+		r.progressBar.SetValue(r.page.progress)
+		r.progressBar.Show()
+	} else {
+		r.progressBar.Hide()
+	}
+
 	r.statusText.Refresh()
 	r.explanationText.Refresh()
 }
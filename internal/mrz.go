@@ -0,0 +1,56 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ubavic/bas-celik/document"
+	"github.com/ubavic/bas-celik/internal/logger"
+)
+
+// Exit code used when the MRZ provided with the -mrz flag doesn't match the data from the chip.
+const ExitCodeMRZMismatch = 5
+
+// Returned when the MRZ doesn't match the data read from the chip.
+type MRZMismatchError struct {
+	Mismatches []string // Names of the document fields that don't match
+}
+
+func (err *MRZMismatchError) Error() string {
+	return "MRZ doesn't match the data from the chip: " + strings.Join(err.Mismatches, ", ")
+}
+
+// Returns the exit code of the program for the error.
+func (err *MRZMismatchError) ExitCode() int {
+	return ExitCodeMRZMismatch
+}
+
+// Compares the MRZ with the ID documents. An error is returned if no ID document is read.
+func checkMRZ(docs []document.Document, text string) error {
+	mrz, err := document.ParseMRZ(text)
+	if err != nil {
+		return fmt.Errorf("parsing MRZ: %w", err)
+	}
+
+	compared := false
+	for _, doc := range docs {
+		idDoc, ok := doc.(*document.IdDocument)
+		if !ok {
+			continue
+		}
+
+		compared = true
+		mismatches := idDoc.CompareMRZ(mrz)
+		if len(mismatches) > 0 {
+			return &MRZMismatchError{Mismatches: mismatches}
+		}
+	}
+
+	if !compared {
+		return errors.New("MRZ can be compared only with ID documents")
+	}
+
+	logger.Info("MRZ matches the data from the chip")
+	return nil
+}
@@ -1,23 +1,36 @@
 package internal
 
 import (
+	"context"
 	"embed"
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/ebfe/scard"
 	"github.com/ubavic/bas-celik/card"
 	"github.com/ubavic/bas-celik/document"
+	"github.com/ubavic/bas-celik/internal/logger"
 )
 
 type LaunchConfig struct {
+	AtrTablePath          string
 	PdfPath               string
 	JsonPath              string
 	ExcelPath             string
 	Verbose               bool
+	Exclusive             bool
+	EU                    bool
+	CheckValidity         bool
+	MRZ                   string
 	GetValidUntilFromRfzo bool
 	Reader                uint
+	Timeout               time.Duration
+	Parts                 card.Part
 	EmbedDirectory        embed.FS
 }
 
@@ -60,31 +73,121 @@ func readAndSave(cfg LaunchConfig) error {
 		return fmt.Errorf("only %d readers found", len(readersNames))
 	}
 
-	sCard, err := ctx.Connect(readersNames[cfg.Reader], scard.ShareShared, scard.ProtocolAny)
+	shareMode := scard.ShareShared
+	if cfg.Exclusive {
+		shareMode = scard.ShareExclusive
+	}
+
+	sCard, err := ctx.Connect(readersNames[cfg.Reader], shareMode, scard.ProtocolAny)
 	if err != nil {
 		return fmt.Errorf("connecting reader %s: %w", readersNames[cfg.Reader], err)
 	}
 
 	defer sCard.Disconnect(scard.LeaveCard)
 
-	cardDoc, err := card.DetectCardDocument(sCard)
-	if err != nil {
+	readCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		readCtx, cancel = context.WithTimeout(readCtx, cfg.Timeout)
+		defer cancel()
+	}
+
+	// When the read is aborted, the default handling of the interrupt is restored, so the second Ctrl+C terminates the program.
+	// Blocked PC/SC calls are canceled and the card is disconnected, so that a stuck reader doesn't hang the read.
+	stopAbort := context.AfterFunc(readCtx, func() {
+		stop()
+		ctx.Cancel()
+		sCard.Disconnect(scard.LeaveCard)
+	})
+	defer stopAbort()
+
+	session := card.NewSession(sCard, shareMode, card.DefaultRetries)
+	session.OnRetry = logRetry
+
+	cardDocs, err := card.DetectCardDocumentsContext(readCtx, session)
+	if errors.Is(err, card.ErrUnknownCard) {
+		return fmt.Errorf("detecting card type: %w (use -report option to create a report about the card)", err)
+	} else if err != nil {
 		return fmt.Errorf("detecting card type: %w", err)
 	}
 
-	err = cardDoc.InitCard()
-	if err != nil {
-		return fmt.Errorf("initializing card: %w", err)
+	if card.ShouldReportAtr(cardDocs[0]) {
+		fmt.Println("Card ATR", cardDocs[0].Atr(), "is not known. Please report it at https://github.com/ubavic/bas-celik/issues")
 	}
 
-	err = cardDoc.ReadCard()
+	docs := make([]document.Document, 0, len(cardDocs))
+	for _, cardDoc := range cardDocs {
+		doc, err := readDocument(readCtx, session, cardDoc, cfg)
+		if err != nil {
+			return err
+		}
+
+		err = saveDocument(doc, cfg, len(cardDocs) > 1)
+		if err != nil {
+			return err
+		}
+
+		docs = append(docs, doc)
+	}
+
+	return checkDocuments(docs, cfg)
+}
+
+// Evaluates the MRZ and the validity checks, and reports the failures of both checks.
+// The MRZ mismatch is reported first, so its exit code takes precedence.
+// Errors that prevent the MRZ comparison (e.g. a malformed MRZ) are returned immediately.
+func checkDocuments(docs []document.Document, cfg LaunchConfig) error {
+	failures := []error{}
+
+	if len(cfg.MRZ) > 0 {
+		err := checkMRZ(docs, cfg.MRZ)
+		var mismatchErr *MRZMismatchError
+		if err != nil && !errors.As(err, &mismatchErr) {
+			return err
+		}
+
+		failures = append(failures, err)
+	}
+
+	if cfg.CheckValidity {
+		failures = append(failures, checkValidity(docs, time.Now()))
+	}
+
+	return errors.Join(failures...)
+}
+
+func readDocument(ctx context.Context, session *card.Session, cardDoc card.CardDocument, cfg LaunchConfig) (document.Document, error) {
+	session.SetInit(cardDoc.InitCard)
+
+	readOptions := card.ReadOptions{Parts: cfg.Parts}
+	if cfg.Verbose {
+		readOptions.Progress = logProgress
+	}
+	cardDoc.SetReadOptions(readOptions)
+
+	// Other applications can't interleave their commands during the transaction.
+	err := session.Transaction(func() error {
+		err := cardDoc.InitCardContext(ctx)
+		if err != nil {
+			return fmt.Errorf("initializing card: %w", err)
+		}
+
+		err = cardDoc.ReadCardContext(ctx)
+		if err != nil {
+			return fmt.Errorf("reading card: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("reading card: %w", err)
+		return nil, err
 	}
 
 	doc, err := cardDoc.GetDocument()
 	if err != nil {
-		return fmt.Errorf("getting document: %w", err)
+		return nil, fmt.Errorf("getting document: %w", err)
 	}
 
 	switch doc := doc.(type) {
@@ -92,46 +195,93 @@ func readAndSave(cfg LaunchConfig) error {
 		if cfg.GetValidUntilFromRfzo {
 			err := doc.UpdateValidUntilDateFromRfzo()
 			if err != nil {
-				return fmt.Errorf("updating `ValidUntil` date: %w", err)
+				return nil, fmt.Errorf("updating `ValidUntil` date: %w", err)
 			}
 		}
 	}
 
-	if len(cfg.PdfPath) > 0 {
+	return doc, nil
+}
+
+// Saves the document to the paths from the config.
+// When the card holds multiple documents, the document type is appended to each file name.
+func saveDocument(doc document.Document, cfg LaunchConfig, multiple bool) error {
+	pdfPath, jsonPath, excelPath := cfg.PdfPath, cfg.JsonPath, cfg.ExcelPath
+	if multiple {
+		pdfPath = documentPath(pdfPath, doc)
+		jsonPath = documentPath(jsonPath, doc)
+		excelPath = documentPath(excelPath, doc)
+	}
+
+	if vehicleDoc, ok := doc.(*document.VehicleDocument); ok && cfg.EU {
+		doc = &document.EUVehicleDocument{Vehicle: vehicleDoc}
+	}
+
+	if len(pdfPath) > 0 {
 		pdf, _, err := doc.BuildPdf()
 		if err != nil {
 			return fmt.Errorf("generating pdf: %w", err)
 		}
 
-		err = os.WriteFile(cfg.PdfPath, pdf, 0600)
+		err = os.WriteFile(pdfPath, pdf, 0600)
 		if err != nil {
-			return fmt.Errorf("writing file %s: %w", cfg.PdfPath, err)
+			return fmt.Errorf("writing file %s: %w", pdfPath, err)
 		}
 	}
 
-	if len(cfg.JsonPath) > 0 {
+	if len(jsonPath) > 0 {
 		json, err := doc.BuildJson()
 		if err != nil {
 			return fmt.Errorf("generating json: %w", err)
 		}
 
-		err = os.WriteFile(cfg.JsonPath, json, 0600)
+		err = os.WriteFile(jsonPath, json, 0600)
 		if err != nil {
-			return fmt.Errorf("writing file %s: %w", cfg.JsonPath, err)
+			return fmt.Errorf("writing file %s: %w", jsonPath, err)
 		}
 	}
 
-	if len(cfg.ExcelPath) > 0 {
+	if len(excelPath) > 0 {
 		excel, _, err := doc.BuildExcel()
 		if err != nil {
 			return fmt.Errorf("generating json: %w", err)
 		}
 
-		err = os.WriteFile(cfg.ExcelPath, excel, 0600)
+		err = os.WriteFile(excelPath, excel, 0600)
 		if err != nil {
-			return fmt.Errorf("writing file %s: %w", cfg.ExcelPath, err)
+			return fmt.Errorf("writing file %s: %w", excelPath, err)
 		}
 	}
 
 	return nil
 }
+
+// Appends the document type to the file name, e.g. `card.pdf` becomes `card-medical.pdf`.
+func documentPath(path string, doc document.Document) string {
+	if len(path) == 0 {
+		return path
+	}
+
+	name := "document"
+	switch doc.(type) {
+	case *document.IdDocument:
+		name = "id"
+	case *document.MedicalDocument:
+		name = "medical"
+	case *document.VehicleDocument:
+		name = "vehicle"
+	case *document.TachographDocument:
+		name = "tachograph"
+	}
+
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + name + ext
+}
+
+func logProgress(progress card.Progress) {
+	logger.Info(fmt.Sprintf("reading file %d/%d: %d/%d bytes", progress.File, progress.FileCount, progress.BytesRead, progress.FileLength))
+}
+
+func logRetry(attempt int, err error) {
+	logger.Info(fmt.Sprintf("card connection interrupted (%v), reconnecting (attempt %d/%d)", err, attempt, card.DefaultRetries))
+}
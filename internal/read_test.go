@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ubavic/bas-celik/document"
+)
+
+func Test_CheckDocuments(t *testing.T) {
+	docs := []document.Document{
+		&document.IdDocument{},
+		validityDocument{status: document.Expired},
+	}
+
+	cfg := LaunchConfig{
+		MRZ:           "IDSRB01234567842305987711232<< 8705231M3103142SRB<<<<<<<<<<<4 DURDEVIC<COLIC<<LJUBISA<ZARKO<",
+		CheckValidity: true,
+	}
+
+	err := checkDocuments(docs, cfg)
+
+	var mismatchErr *MRZMismatchError
+	if !errors.As(err, &mismatchErr) {
+		t.Errorf("Expected MRZ mismatch, but got %v", err)
+	}
+
+	var validityErr *ValidityError
+	if !errors.As(err, &validityErr) || validityErr.Validity.Status != document.Expired {
+		t.Errorf("Expected expired document, but got %v", err)
+	}
+
+	var exitErr interface{ ExitCode() int }
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != ExitCodeMRZMismatch {
+		t.Errorf("Expected exit code %d of the MRZ mismatch", ExitCodeMRZMismatch)
+	}
+
+	cfg.MRZ = "IDSRB"
+	err = checkDocuments(docs, cfg)
+	if err == nil || errors.As(err, &validityErr) {
+		t.Errorf("Expected malformed MRZ to be reported without the validity, but got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ubavic/bas-celik/document"
+	"github.com/ubavic/bas-celik/internal/logger"
+)
+
+// Exit codes used when the validity is checked with the -checkValidity flag.
+const (
+	ExitCodeExpired         = 3
+	ExitCodeExpiresSoon     = 4
+	ExitCodeValidityUnknown = 6
+)
+
+// Returned when the validity is checked and a read document is expired, expires soon,
+// or its validity is unknown.
+type ValidityError struct {
+	Validity document.Validity
+}
+
+func (err *ValidityError) Error() string {
+	switch err.Validity.Status {
+	case document.Expired:
+		return fmt.Sprintf("document expired on %s", err.Validity.ExpiryDate)
+	case document.ExpiresSoon:
+		return fmt.Sprintf("document expires on %s (%d days remaining)", err.Validity.ExpiryDate, err.Validity.DaysRemaining)
+	default:
+		return "document validity is unknown, since the expiry date is not available"
+	}
+}
+
+// Returns the exit code of the program for the error.
+func (err *ValidityError) ExitCode() int {
+	switch err.Validity.Status {
+	case document.Expired:
+		return ExitCodeExpired
+	case document.ExpiresSoon:
+		return ExitCodeExpiresSoon
+	default:
+		return ExitCodeValidityUnknown
+	}
+}
+
+// Checks validity of the documents on the day of t.
+// The error is returned for the expired document. If none is expired, the error is returned
+// for the document that expires soon, and after that for the document with the unknown validity.
+func checkValidity(docs []document.Document, t time.Time) error {
+	var validityErr *ValidityError
+
+	for _, doc := range docs {
+		checker, ok := doc.(document.ValidityChecker)
+		if !ok {
+			continue
+		}
+
+		validity := checker.ValidityAt(t)
+		logger.Info(fmt.Sprintf("document validity: %s (expiry date %s)", validity.Status, validity.ExpiryDate))
+
+		switch validity.Status {
+		case document.Expired:
+			return &ValidityError{Validity: validity}
+		case document.ExpiresSoon:
+			if validityErr == nil || validityErr.Validity.Status == document.ValidityUnknown {
+				validityErr = &ValidityError{Validity: validity}
+			}
+		case document.ValidityUnknown:
+			if validityErr == nil {
+				validityErr = &ValidityError{Validity: validity}
+			}
+		}
+	}
+
+	if validityErr != nil {
+		return validityErr
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ubavic/bas-celik/document"
+)
+
+// Document with the fixed validity.
+type validityDocument struct {
+	document.Document
+	status document.ValidityStatus
+}
+
+func (doc validityDocument) ValidityAt(_ time.Time) document.Validity {
+	return document.Validity{Status: doc.status}
+}
+
+func Test_CheckValidity(t *testing.T) {
+	testCases := []struct {
+		name             string
+		statuses         []document.ValidityStatus
+		expectedExitCode int
+	}{
+		{name: "valid", statuses: []document.ValidityStatus{document.Valid}, expectedExitCode: 0},
+		{name: "expires soon", statuses: []document.ValidityStatus{document.ExpiresSoon}, expectedExitCode: ExitCodeExpiresSoon},
+		{name: "expired", statuses: []document.ValidityStatus{document.Expired}, expectedExitCode: ExitCodeExpired},
+		{name: "unknown", statuses: []document.ValidityStatus{document.ValidityUnknown}, expectedExitCode: ExitCodeValidityUnknown},
+		{name: "unknown and expires soon", statuses: []document.ValidityStatus{document.ValidityUnknown, document.ExpiresSoon}, expectedExitCode: ExitCodeExpiresSoon},
+		{name: "expires soon and expired", statuses: []document.ValidityStatus{document.ExpiresSoon, document.Expired}, expectedExitCode: ExitCodeExpired},
+		{name: "without validity", statuses: []document.ValidityStatus{}, expectedExitCode: 0},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			docs := []document.Document{}
+			for _, status := range testCase.statuses {
+				docs = append(docs, validityDocument{status: status})
+			}
+
+			exitCode := 0
+			err := checkValidity(docs, time.Now())
+			var validityErr *ValidityError
+			if errors.As(err, &validityErr) {
+				exitCode = validityErr.ExitCode()
+			} else if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+
+			if exitCode != testCase.expectedExitCode {
+				t.Errorf("Expected exit code %d, but got %d", testCase.expectedExitCode, exitCode)
+			}
+		})
+	}
+}
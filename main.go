@@ -1,8 +1,11 @@
 package main
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 
+	"github.com/ubavic/bas-celik/card"
 	"github.com/ubavic/bas-celik/document"
 	"github.com/ubavic/bas-celik/internal"
 	"github.com/ubavic/bas-celik/internal/logger"
@@ -37,13 +40,35 @@ func main() {
 		os.Exit(1)
 	}
 
-	err = internal.Run(cfg)
+	err = configCardPackage(cfg.AtrTablePath)
 	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
+	err = internal.Run(cfg)
+	var exitErr interface{ ExitCode() int }
+	if errors.As(err, &exitErr) {
+		printCheckFailures(err)
+		os.Exit(exitErr.ExitCode())
+	} else if err != nil {
 		fmt.Println("Error:", err)
 		os.Exit(2)
 	}
 }
 
+// Prints each failed check of the read documents (such as the MRZ mismatch) on a separate line.
+func printCheckFailures(err error) {
+	failures := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		failures = joined.Unwrap()
+	}
+
+	for _, failure := range failures {
+		fmt.Println("Check failed:", failure)
+	}
+}
+
 func configDocumentPackage() error {
 	documentConfig := document.DocumentConfig{}
 	var err error
@@ -70,3 +95,35 @@ func configDocumentPackage() error {
 
 	return nil
 }
+
+// Loads user ATR table from the given path. If path is not set,
+// the table is loaded from the user config directory, if it exists there.
+// A table from the config directory that can't be loaded is reported and ignored,
+// so the program continues with the embedded table.
+func configCardPackage(atrTablePath string) error {
+	if len(atrTablePath) > 0 {
+		err := card.LoadAtrTable(atrTablePath)
+		if err != nil {
+			return fmt.Errorf("loading ATR table: %w", err)
+		}
+
+		return nil
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil
+	}
+
+	atrTablePath = filepath.Join(configDir, "bas-celik", "atr.json")
+	if _, err := os.Stat(atrTablePath); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	err = card.LoadAtrTable(atrTablePath)
+	if err != nil {
+		fmt.Println("Warning: ignoring ATR table:", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+// Package pages holds the registry of GUI pages that show documents.
+// Packages that add new card types (see card.RegisterCardType) register pages for their documents here,
+// usually in the init function, and the GUI shows the registered page for each document it reads.
+package pages
+
+import (
+	"reflect"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"github.com/ubavic/bas-celik/document"
+)
+
+// Describes how a document of type T is shown in the GUI.
+type Page[T document.Document] struct {
+	Title   string                      // Translation identifier of the tab title. Used as the title if there is no translation
+	Content func(T) *fyne.Container     // Creates the content of the page
+	Buttons func(T) []fyne.CanvasObject // Creates additional buttons, shown before the save buttons. Can be nil
+}
+
+// Registered page that accepts any document. Documents passed to the functions must be of the registered type.
+type Registered struct {
+	Title   string
+	Content func(document.Document) *fyne.Container
+	Buttons func(document.Document) []fyne.CanvasObject // Nil if the page doesn't have additional buttons
+}
+
+var mu sync.RWMutex
+
+var pages = map[reflect.Type]Registered{}
+
+// Registers the page for documents of type T. Registering a page for the same type again replaces the page.
+// Pages should be registered before the GUI is started.
+func Register[T document.Document](page Page[T]) {
+	registered := Registered{
+		Title: page.Title,
+		Content: func(doc document.Document) *fyne.Container {
+			return page.Content(doc.(T))
+		},
+	}
+
+	if page.Buttons != nil {
+		registered.Buttons = func(doc document.Document) []fyne.CanvasObject {
+			return page.Buttons(doc.(T))
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	pages[reflect.TypeFor[T]()] = registered
+}
+
+// Returns the registered page for the document.
+func Lookup(doc document.Document) (Registered, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	page, ok := pages[reflect.TypeOf(doc)]
+	return page, ok
+}
@@ -0,0 +1,41 @@
+package pages_test
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"github.com/ubavic/bas-celik/document"
+	"github.com/ubavic/bas-celik/pages"
+)
+
+func Test_Register(t *testing.T) {
+	content := &fyne.Container{}
+	var shown *document.TachographDocument
+
+	pages.Register(pages.Page[*document.TachographDocument]{
+		Title: "tachograph",
+		Content: func(doc *document.TachographDocument) *fyne.Container {
+			shown = doc
+			return content
+		},
+	})
+
+	doc := &document.TachographDocument{}
+	page, ok := pages.Lookup(doc)
+	if !ok {
+		t.Fatalf("Expected page to be registered")
+	}
+
+	if page.Title != "tachograph" || page.Buttons != nil {
+		t.Errorf("Unexpected page %+v", page)
+	}
+
+	if page.Content(doc) != content || shown != doc {
+		t.Errorf("Expected content to be created for the document")
+	}
+
+	_, ok = pages.Lookup(&document.MedicalDocument{})
+	if ok {
+		t.Errorf("Expected no page for the medical document")
+	}
+}